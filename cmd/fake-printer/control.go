@@ -0,0 +1,223 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/Eric-Eklund/epson-printing/pkg/printer/ipptest"
+)
+
+// condition is a printer state that can be switched on via /control
+type condition struct {
+	state   int
+	reason  string
+	message string
+}
+
+// conditions are the states accepted by /control?state=
+var conditions = map[string]condition{
+	"idle":        {ipptest.PrinterIdle, "", ""},
+	"stopped":     {ipptest.PrinterStopped, "paused", "Printer stopped"},
+	"media-empty": {ipptest.PrinterStopped, "media-empty-error", "Paper out. Load paper and press OK"},
+	"jam":         {ipptest.PrinterStopped, "media-jam-error", "Paper jam. Remove the jammed paper"},
+}
+
+// status is the JSON document returned by /control
+type status struct {
+	State        int              `json:"state"`
+	StateReasons []string         `json:"state_reasons"`
+	StateMessage string           `json:"state_message,omitempty"`
+	Markers      []ipptest.Marker `json:"markers"`
+	Jobs         int              `json:"jobs"`
+}
+
+// control shows the printer status on GET and changes it on POST:
+//
+//	curl -X POST 'localhost:8631/control?state=jam'
+//	curl -X POST 'localhost:8631/control?state=idle'
+//	curl -X POST 'localhost:8631/control?refill=Cyan'   # or refill=all
+//	curl -X POST 'localhost:8631/control?marker=Yellow&level=5'
+//	curl -X POST 'localhost:8631/control?waste=100'       # Maintenance box full
+func (fp *fakePrinter) control(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPost:
+		if err := fp.apply(r); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	default:
+		http.Error(w, "use GET or POST", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var s status
+	fp.printer.Update(func(p *ipptest.Printer) {
+		s = status{
+			State:        p.State,
+			StateReasons: slices.Clone(p.StateReasons),
+			StateMessage: p.StateMessage,
+			Markers:      slices.Clone(p.Markers),
+		}
+	})
+	s.Jobs = len(fp.printer.Jobs())
+
+	w.Header().Set("Content-Type", "application/json")
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	_ = encoder.Encode(s)
+}
+
+// apply changes the printer state or ink levels from the request parameters
+func (fp *fakePrinter) apply(r *http.Request) error {
+	if err := r.ParseForm(); err != nil {
+		return err
+	}
+
+	var err error
+	fp.printer.Update(func(p *ipptest.Printer) {
+		if name := r.Form.Get("state"); name != "" {
+			c, ok := conditions[name]
+			if !ok {
+				err = fmt.Errorf("unknown state %q (use idle, stopped, media-empty or jam)", name)
+				return
+			}
+			setCondition(p, c)
+		}
+
+		if name := r.Form.Get("refill"); name != "" {
+			err = setMarkerLevel(p, name, 100)
+		}
+		if name := r.Form.Get("marker"); name != "" && err == nil {
+			level, convErr := strconv.Atoi(r.Form.Get("level"))
+			if convErr != nil || level < 0 || level > 100 {
+				err = fmt.Errorf("invalid level %q (use 0-100)", r.Form.Get("level"))
+				return
+			}
+			err = setMarkerLevel(p, name, level)
+		}
+		if waste := r.Form.Get("waste"); waste != "" && err == nil {
+			level, convErr := strconv.Atoi(waste)
+			if convErr != nil {
+				err = fmt.Errorf("invalid waste level %q (use 0-100)", waste)
+				return
+			}
+			err = setWasteLevel(p, level)
+		}
+		updateSupplyReasons(p)
+	})
+	return err
+}
+
+// maintenanceBox is the name of the waste ink marker
+const maintenanceBox = "Maintenance Box"
+
+// wasteFull is the condition of a printer with a full maintenance box
+var wasteFull = condition{ipptest.PrinterStopped, "", "Maintenance box full. Replace the maintenance box"}
+
+// isWaste reports whether a marker is the maintenance box
+func isWaste(m ipptest.Marker) bool {
+	return m.Type == "waste-ink"
+}
+
+// setWasteLevel fills the maintenance box to a level. A full box stops the
+// printer, as the real printer does, and emptying it resumes printing.
+func setWasteLevel(p *ipptest.Printer, level int) error {
+	i := slices.IndexFunc(p.Markers, isWaste)
+	if i < 0 {
+		return fmt.Errorf("the printer has no maintenance box")
+	}
+	if level < 0 || level > 100 {
+		return fmt.Errorf("invalid waste level %d (use 0-100)", level)
+	}
+
+	p.Markers[i].Level = level
+	switch {
+	case level >= 100:
+		setCondition(p, wasteFull)
+	case p.StateMessage == wasteFull.message:
+		setCondition(p, conditions["idle"])
+	}
+	updateSupplyReasons(p)
+	return nil
+}
+
+// setCondition switches the printer state. Jobs stay pending while the
+// printer is stopped and continue once it is idle again.
+func setCondition(p *ipptest.Printer, c condition) {
+	p.State = c.state
+	p.StateMessage = c.message
+	p.StateReasons = slices.DeleteFunc(p.StateReasons, func(reason string) bool {
+		return !strings.HasPrefix(reason, "marker-")
+	})
+	if c.reason != "" {
+		p.StateReasons = append(p.StateReasons, c.reason)
+	}
+
+	if c.state == ipptest.PrinterStopped {
+		p.JobProgression = []int{ipptest.JobPending}
+	} else {
+		p.JobProgression = []int{ipptest.JobPending, ipptest.JobProcessing, ipptest.JobCompleted}
+	}
+}
+
+// setMarkerLevel sets the level of the named ink tank, or of all tanks
+func setMarkerLevel(p *ipptest.Printer, name string, level int) error {
+	found := false
+	for i := range p.Markers {
+		if isWaste(p.Markers[i]) {
+			continue // Changed with setWasteLevel
+		}
+		if name == "all" || strings.EqualFold(p.Markers[i].Name, name) {
+			p.Markers[i].Level = level
+			found = true
+		}
+	}
+	if !found {
+		return fmt.Errorf("unknown marker %q", name)
+	}
+	return nil
+}
+
+// updateSupplyReasons reports low and empty ink tanks and the maintenance
+// box in printer-state-reasons, as the real printer does
+func updateSupplyReasons(p *ipptest.Printer) {
+	low, empty := false, false
+	wasteAlmostFull, wasteFull := false, false
+	for _, m := range p.Markers {
+		switch {
+		case isWaste(m):
+			wasteFull = m.Level >= 100
+			wasteAlmostFull = m.Level >= m.HighLevel
+		case m.Level <= 0:
+			empty = true
+		case m.Level <= m.LowLevel:
+			low = true
+		}
+	}
+
+	reasons := slices.DeleteFunc(p.StateReasons, func(reason string) bool {
+		return reason == "none" || strings.HasPrefix(reason, "marker-supply-") ||
+			strings.HasPrefix(reason, "marker-waste-")
+	})
+	if low {
+		reasons = append(reasons, "marker-supply-low-warning")
+	}
+	if empty {
+		reasons = append(reasons, "marker-supply-empty-error")
+	}
+	switch {
+	case wasteFull:
+		reasons = append(reasons, "marker-waste-full-error")
+	case wasteAlmostFull:
+		reasons = append(reasons, "marker-waste-almost-full-warning")
+	}
+	if len(reasons) == 0 {
+		reasons = []string{"none"}
+	}
+	p.StateReasons = reasons
+}
@@ -0,0 +1,218 @@
+// Command fake-printer runs an emulated Epson ET-8550 IPP printer on
+// localhost for development without hardware:
+//
+//	go run ./cmd/fake-printer
+//	PRINTER_URI=http://localhost:8631/ipp/print print test
+//
+// Received documents are saved to a directory, the ink tanks deplete with
+// every page printed, and error states and the maintenance box can be
+// changed through the /control endpoint. -model emulates an ET-8500 or
+// ET-2850 instead.
+package main
+
+import (
+	"cmp"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/Eric-Eklund/epson-printing/pkg/printer"
+	"github.com/Eric-Eklund/epson-printing/pkg/printer/ipptest"
+)
+
+func main() {
+	addr := flag.String("addr", "localhost:8631", "Address to listen on")
+	modelName := flag.String("model", "ET-8550", "Printer model to emulate: ET-8550, ET-8500 or ET-2850")
+	dir := flag.String("dir", "fake-printer-jobs", "Directory to save received documents to")
+	levels := flag.String("levels", "",
+		"Initial ink levels in percent, one per tank of the model (default: all full)")
+	inkPerPage := flag.Int("ink-per-page", 1, "Percent of each ink tank used per printed page")
+	waste := flag.Int("waste", 20, "Initial maintenance box level in percent full (100 stops the printer)")
+	flag.Parse()
+
+	model, err := printer.LookupModel(*modelName)
+	if err != nil {
+		log.Fatalf("Error: -model: %v\n", err)
+	}
+	p := ipptest.NewPrinter()
+	emulateModel(p, model)
+	if *levels != "" {
+		if err := setLevels(p, *levels); err != nil {
+			log.Fatalf("Error: -levels: %v\n", err)
+		}
+	}
+	if err := setWasteLevel(p, *waste); err != nil {
+		log.Fatalf("Error: -waste: %v\n", err)
+	}
+	if err := os.MkdirAll(*dir, 0o755); err != nil {
+		log.Fatalf("Error: %v\n", err)
+	}
+
+	fp := &fakePrinter{printer: p, dir: *dir, inkPerPage: *inkPerPage}
+	p.OnJob = fp.onJob
+
+	mux := http.NewServeMux()
+	mux.Handle("/ipp/print", p)
+	mux.HandleFunc("/control", fp.control)
+
+	fmt.Println("=========================================")
+	fmt.Printf("FAKE PRINTER (%s)\n", model.Name)
+	fmt.Println("=========================================")
+	fmt.Printf("Printer URI: http://%s/ipp/print\n", *addr)
+	fmt.Printf("Control:     http://%s/control\n", *addr)
+	fmt.Printf("Documents:   %s\n", *dir)
+	fmt.Println("=========================================")
+	fmt.Printf("\nexport PRINTER_URI=http://%s/ipp/print\n\n", *addr)
+
+	log.Fatal(http.ListenAndServe(*addr, mux))
+}
+
+// inkColors are the marker colors reported for the ink tanks
+var inkColors = map[string]string{
+	"Black":       "#000000",
+	"Photo Black": "#000000",
+	"Cyan":        "#00FFFF",
+	"Magenta":     "#FF00FF",
+	"Yellow":      "#FFFF00",
+	"Gray":        "#808080",
+}
+
+// emulateModel changes the emulated printer to the inks, trays and paper
+// sizes of a model
+func emulateModel(p *ipptest.Printer, model printer.Model) {
+	p.Info = model.Name + " Series"
+	p.MakeAndModel = model.Name + " Series"
+
+	p.Markers = p.Markers[:0]
+	for _, ink := range model.Inks {
+		p.Markers = append(p.Markers, ipptest.Marker{
+			Name: ink, Color: inkColors[ink], Type: "ink-bottle",
+			Level: 100, LowLevel: 15, HighLevel: 100,
+		})
+	}
+	p.Markers = append(p.Markers, ipptest.Marker{
+		Name: maintenanceBox, Color: "none", Type: "waste-ink", HighLevel: 90,
+	})
+
+	p.MediaSources = p.MediaSources[:0]
+	for _, tray := range model.Trays {
+		p.MediaSources = append(p.MediaSources, strings.ToLower(tray))
+	}
+	p.Media = slices.DeleteFunc(p.Media, func(media string) bool {
+		return !model.SupportsPaper(media)
+	})
+}
+
+// setLevels sets the ink tank levels from a comma-separated list
+func setLevels(p *ipptest.Printer, list string) error {
+	values := strings.Split(list, ",")
+	tanks := slices.IndexFunc(p.Markers, isWaste)
+	if tanks < 0 {
+		tanks = len(p.Markers)
+	}
+	if len(values) != tanks {
+		return fmt.Errorf("need %d levels, got %d", tanks, len(values))
+	}
+
+	for i, v := range values {
+		level, err := strconv.Atoi(strings.TrimSpace(v))
+		if err != nil || level < 0 || level > 100 {
+			return fmt.Errorf("invalid level %q (use 0-100)", v)
+		}
+		p.Markers[i].Level = level
+	}
+	updateSupplyReasons(p)
+	return nil
+}
+
+// fakePrinter saves received jobs and depletes ink as they print
+type fakePrinter struct {
+	printer    *ipptest.Printer
+	dir        string
+	inkPerPage int
+}
+
+// onJob saves the documents of a finished job and uses ink for its pages
+func (fp *fakePrinter) onJob(job ipptest.Job) {
+	pages := 0
+	for i, doc := range job.Documents {
+		name := fmt.Sprintf("job-%04d-%d%s", job.ID, i+1, extension(job.Format))
+		if job.Name != "" {
+			name = fmt.Sprintf("job-%04d-%d-%s", job.ID, i+1, filepath.Base(job.Name))
+		}
+		path := filepath.Join(fp.dir, name)
+		if err := os.WriteFile(path, doc, 0o644); err != nil {
+			log.Printf("Job %d: saving document: %v", job.ID, err)
+		}
+		pages += countPages(doc, job.Format)
+	}
+	pages *= max(copies(job), 1)
+
+	fp.printer.Update(func(p *ipptest.Printer) {
+		for i := range p.Markers {
+			if !isWaste(p.Markers[i]) {
+				p.Markers[i].Level = max(p.Markers[i].Level-pages*fp.inkPerPage, 0)
+			}
+		}
+		updateSupplyReasons(p)
+	})
+
+	log.Printf("Job %d %q from %s: %d page(s), %d document(s) saved to %s",
+		job.ID, job.Name, cmp.Or(job.User, "unknown user"), pages, len(job.Documents), fp.dir)
+}
+
+// countPages estimates the number of pages in a document: PDF page
+// objects, or one page for images
+func countPages(doc []byte, format string) int {
+	if format != "application/pdf" && !strings.HasPrefix(string(doc), "%PDF") {
+		return 1
+	}
+
+	// Count "/Type /Page" objects but not the "/Type /Pages" tree nodes
+	s := string(doc)
+	pages := 0
+	for {
+		i := strings.Index(s, "/Type")
+		if i < 0 {
+			break
+		}
+		s = strings.TrimLeft(s[i+len("/Type"):], " \r\n\t")
+		if strings.HasPrefix(s, "/Page") && !strings.HasPrefix(s, "/Pages") {
+			pages++
+		}
+	}
+	return max(pages, 1)
+}
+
+// copies returns the copies job attribute, or 0 when not set
+func copies(job ipptest.Job) int {
+	for _, attr := range job.Attributes {
+		if attr.Name == "copies" && len(attr.Values) > 0 {
+			n, _ := strconv.Atoi(attr.Values[0].V.String())
+			return n
+		}
+	}
+	return 0
+}
+
+// extension returns a file extension for a document format
+func extension(format string) string {
+	switch format {
+	case "application/pdf":
+		return ".pdf"
+	case "image/jpeg":
+		return ".jpg"
+	case "image/png":
+		return ".png"
+	case "image/pwg-raster":
+		return ".pwg"
+	default:
+		return ".bin"
+	}
+}
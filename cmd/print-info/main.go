@@ -11,7 +11,8 @@ import (
 func main() {
 	fmt.Println("=============================================")
 	fmt.Println("Epson ET-8550 Status Report")
-	fmt.Println("=============================================\n")
+	fmt.Println("=============================================")
+	fmt.Println()
 
 	// Get printer URI from environment variable
 	printerURI := os.Getenv("PRINTER_URI")
@@ -36,7 +37,7 @@ func main() {
 	fmt.Printf("✓ Print job sent! (Job ID: %d)\n", jobID)
 	fmt.Println("\nReport contains:")
 	fmt.Println("  - Printer information")
-	fmt.Println("  - Ink levels for all 6 tanks")
+	fmt.Println("  - Ink levels for all tanks")
 	fmt.Println("  - Program information")
 	fmt.Println("  - Date and time")
 	fmt.Println("\nPDF file saved for reference:", pdfPath)
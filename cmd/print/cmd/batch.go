@@ -0,0 +1,201 @@
+package cmd
+
+import (
+	"cmp"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Eric-Eklund/epson-printing/pkg/printer"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Batch command flags
+	batchProfileFlag string
+	recursiveFlag    bool
+	includeFlag      []string
+	excludeFlag      []string
+)
+
+// defaultBatchPatterns matches the PDFs and images PrintDocument can handle
+var defaultBatchPatterns = []string{"*.pdf", "*.jpg", "*.jpeg", "*.png", "*.tif", "*.tiff"}
+
+// batchCmd represents the batch command
+var batchCmd = &cobra.Command{
+	Use:   "batch <folder> [profile]",
+	Short: "Print all matching files in a folder",
+	Long: `Print every file in a folder using a single print profile.
+
+Files are matched against --include glob patterns (case-insensitive) and
+skipped when they match any --exclude pattern. Use --recursive to descend
+into subfolders. Each file is sent as its own print job and a summary of
+job IDs and failures is shown at the end.
+
+Profile can be specified as a numeric ID or profile name, exactly like
+the main print command. Use 'print list' to see all available profiles.`,
+	Example: `  # Print all PDFs and images in a folder with the default profile
+  print batch ~/Exports
+
+  # Print 4x6" glossy photos from a folder and its subfolders
+  print batch ~/Exports 1 --recursive
+
+  # Only print calendar pages, skip drafts
+  print batch ~/Calendar 14 --include "page-*.pdf" --exclude "*draft*"`,
+	Args: cobra.RangeArgs(1, 2),
+	Run:  runBatch,
+}
+
+func init() {
+	rootCmd.AddCommand(batchCmd)
+
+	batchCmd.Flags().StringVarP(&batchProfileFlag, "profile", "p", "",
+		"Profile name or ID (alternative to positional)")
+	batchCmd.Flags().BoolVarP(&recursiveFlag, "recursive", "r", false,
+		"Include files in subfolders")
+	batchCmd.Flags().StringSliceVar(&includeFlag, "include", defaultBatchPatterns,
+		"Glob patterns of files to print")
+	batchCmd.Flags().StringSliceVar(&excludeFlag, "exclude", nil,
+		"Glob patterns of files to skip")
+}
+
+// batchResult holds the outcome of a single file in a batch
+type batchResult struct {
+	file   string
+	result *printer.JobResult
+	err    error
+}
+
+func runBatch(cmd *cobra.Command, args []string) {
+	requirePrinterURI()
+
+	folder := args[0]
+	profile := batchProfileFlag
+	if profile == "" && len(args) >= 2 {
+		profile = args[1]
+	}
+	if profile == "" {
+		profile = cmp.Or(target.Profile, "default")
+	}
+
+	opts, err := getOptionsFromProfile(profile)
+	if err != nil {
+		log.Fatalf("Error: %v\n", err)
+	}
+
+	files, err := collectBatchFiles(folder, recursiveFlag, includeFlag, excludeFlag)
+	if err != nil {
+		log.Fatalf("Error: %v\n", err)
+	}
+	if len(files) == 0 {
+		log.Fatalf("Error: no files matching %s found in %s\n",
+			strings.Join(includeFlag, ", "), folder)
+	}
+
+	client := newClient()
+	validateOptions(cmd, client, opts)
+
+	fmt.Println("=========================================")
+	fmt.Println("BATCH PRINT")
+	fmt.Println("=========================================")
+	fmt.Printf("Folder:      %s\n", folder)
+	fmt.Printf("Files:       %d\n", len(files))
+	fmt.Printf("Profile:     %s\n", profile)
+	fmt.Printf("Settings:    %s on %s (%s, quality: %d)\n",
+		opts.PaperSize, opts.MediaType, opts.Tray, opts.Quality)
+	fmt.Println("=========================================")
+	fmt.Println()
+
+	results := make([]batchResult, 0, len(files))
+	for _, file := range files {
+		if cmd.Context().Err() != nil {
+			fmt.Println("Interrupted, skipping remaining files")
+			break
+		}
+		fmt.Printf("Printing: %s\n", file)
+		result, err := client.PrintDocument(cmd.Context(), file, opts)
+		results = append(results, batchResult{file: file, result: result, err: err})
+	}
+
+	if failed := printBatchSummary(results); failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// collectBatchFiles returns all files below folder whose base name matches
+// one of the include patterns and none of the exclude patterns
+func collectBatchFiles(folder string, recursive bool, include, exclude []string) ([]string, error) {
+	// Validate patterns up front so a typo doesn't silently match nothing
+	for _, pattern := range append(append([]string{}, include...), exclude...) {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+	}
+
+	var files []string
+	err := filepath.WalkDir(folder, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != folder && !recursive {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		name := d.Name()
+		if matchesAny(name, include) && !matchesAny(name, exclude) {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("reading folder: %w", err)
+	}
+
+	return files, nil
+}
+
+// matchesAny reports whether name matches any of the glob patterns,
+// ignoring case so "*.jpg" also matches "IMG_001.JPG"
+func matchesAny(name string, patterns []string) bool {
+	name = strings.ToLower(name)
+	for _, pattern := range patterns {
+		if ok, _ := filepath.Match(strings.ToLower(pattern), name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// printBatchSummary prints the per-file results and returns the number of failures
+func printBatchSummary(results []batchResult) int {
+	failed := 0
+
+	fmt.Println("\n=========================================")
+	fmt.Println("BATCH SUMMARY")
+	fmt.Println("=========================================")
+	for _, r := range results {
+		if r.err != nil {
+			failed++
+			fmt.Printf("✗ %s\n    %v\n", r.file, r.err)
+			continue
+		}
+		fmt.Printf("✓ %s (Job ID: %d)\n", r.file, r.result.JobID)
+		if warning := r.result.Warning(); warning != "" {
+			fmt.Printf("    Warning: %s\n", warning)
+		}
+	}
+	fmt.Println("=========================================")
+	fmt.Printf("Sent %d of %d files", len(results)-failed, len(results))
+	if failed > 0 {
+		fmt.Printf(" (%d failed)", failed)
+	}
+	fmt.Println()
+
+	return failed
+}
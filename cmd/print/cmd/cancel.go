@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var allMineFlag bool
+
+// cancelCmd represents the cancel command
+var cancelCmd = &cobra.Command{
+	Use:   "cancel [job-id...]",
+	Short: "Cancel print jobs",
+	Long: `Cancel one or more print jobs by ID, or all of your own jobs with
+--all-mine. Use 'print queue' to find job IDs.`,
+	Example: `  # Cancel a single job
+  print cancel 42
+
+  # Cancel several jobs
+  print cancel 42 43 44
+
+  # Cancel every job you have queued
+  print cancel --all-mine`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if allMineFlag && len(args) > 0 {
+			return fmt.Errorf("job IDs cannot be combined with --all-mine")
+		}
+		if !allMineFlag && len(args) == 0 {
+			return fmt.Errorf("requires at least one job ID or --all-mine")
+		}
+		return nil
+	},
+	Run: runCancel,
+}
+
+func init() {
+	rootCmd.AddCommand(cancelCmd)
+	cancelCmd.Flags().BoolVar(&allMineFlag, "all-mine", false,
+		"Cancel all of your own jobs")
+}
+
+func runCancel(cmd *cobra.Command, args []string) {
+	requirePrinterURI()
+	client := newClient()
+
+	if allMineFlag {
+		if err := client.CancelMyJobs(cmd.Context()); err != nil {
+			log.Fatalf("Error: %v\n", err)
+		}
+		fmt.Println("✓ Canceled all of your jobs")
+		return
+	}
+
+	failed := 0
+	for _, jobID := range parseJobIDs(args) {
+		if err := client.CancelJob(cmd.Context(), jobID); err != nil {
+			fmt.Printf("✗ %v\n", err)
+			failed++
+			continue
+		}
+		fmt.Printf("✓ Canceled job %d\n", jobID)
+	}
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
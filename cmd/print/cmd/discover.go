@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Eric-Eklund/epson-printing/pkg/printer"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Discover flags
+	discoverDurationFlag time.Duration
+	discoverSaveFlag     int
+)
+
+// discoverCmd represents the discover command
+var discoverCmd = &cobra.Command{
+	Use:   "discover",
+	Short: "Find IPP printers on the local network",
+	Long: `Browse the local network via mDNS (Bonjour) for printers that
+advertise IPP (_ipp._tcp) or IPP over TLS (_ipps._tcp) and list their
+printer URIs.
+
+With --save the chosen URI is written to config.json in the config
+directory ($XDG_CONFIG_HOME/epson-printing, usually ~/.config/epson-printing)
+and used whenever neither --printer nor PRINTER_URI is set.`,
+	Example: `  # List printers on the network
+  print discover
+
+  # Listen longer on a slow network
+  print discover --duration 10s
+
+  # Use the first printer found from now on
+  print discover --save
+
+  # Use the second printer in the list
+  print discover --save 2`,
+	Args: cobra.NoArgs,
+	Run:  runDiscover,
+}
+
+func init() {
+	rootCmd.AddCommand(discoverCmd)
+
+	discoverCmd.Flags().DurationVar(&discoverDurationFlag, "duration", printer.DefaultDiscoverTimeout,
+		"How long to listen for printers")
+	discoverCmd.Flags().IntVar(&discoverSaveFlag, "save", 0,
+		"Save the URI of the printer with this number to the config file")
+	discoverCmd.Flags().Lookup("save").NoOptDefVal = "1"
+	discoverCmd.Flags().BoolVar(&jsonOutput, "json", false, "Output in JSON format")
+}
+
+func runDiscover(cmd *cobra.Command, _ []string) {
+	if !jsonOutput {
+		fmt.Printf("Looking for printers (%s)...\n\n", discoverDurationFlag)
+	}
+
+	printers, err := printer.Discover(cmd.Context(), discoverDurationFlag)
+	if err != nil {
+		log.Fatalf("Error: %v\n", err)
+	}
+
+	if jsonOutput {
+		data, err := json.MarshalIndent(printers, "", "  ")
+		if err != nil {
+			log.Fatalf("Error: %v\n", err)
+		}
+		fmt.Println(string(data))
+	} else {
+		printDiscovered(printers)
+	}
+
+	if discoverSaveFlag == 0 {
+		return
+	}
+	if discoverSaveFlag < 0 || discoverSaveFlag > len(printers) {
+		log.Fatalf("Error: --save %d: found %d printer(s)\n", discoverSaveFlag, len(printers))
+	}
+
+	cfg, err := openConfig()
+	if err != nil {
+		log.Fatalf("Error: %v\n", err)
+	}
+	cfg.PrinterURI = printers[discoverSaveFlag-1].URI
+	if err := cfg.Save(); err != nil {
+		log.Fatalf("Error: %v\n", err)
+	}
+
+	// Keep stdout a valid JSON document
+	if jsonOutput {
+		fmt.Fprintf(os.Stderr, "✓ Saved printer URI %s to %s\n", cfg.PrinterURI, cfg.Path)
+		return
+	}
+	fmt.Printf("\n✓ Saved printer URI %s to %s\n", cfg.PrinterURI, cfg.Path)
+}
+
+// printDiscovered lists discovered printers with the numbers used by --save
+func printDiscovered(printers []printer.DiscoveredPrinter) {
+	if len(printers) == 0 {
+		fmt.Println("No printers found. Make sure the printer is on and on the same network.")
+		return
+	}
+
+	fmt.Println("Printers found:")
+	fmt.Println("===============")
+	for i, p := range printers {
+		fmt.Printf("\n%2d  %s\n", i+1, p.Name)
+		fmt.Printf("    URI:     %s\n", p.URI)
+		if p.Model != "" {
+			fmt.Printf("    Model:   %s\n", p.Model)
+		}
+		if len(p.Formats) > 0 {
+			fmt.Printf("    Formats: %s\n", strings.Join(p.Formats, ", "))
+		}
+		if p.UUID != "" {
+			fmt.Printf("    UUID:    %s\n", p.UUID)
+		}
+	}
+	fmt.Println()
+	fmt.Println("Use one with --printer or PRINTER_URI, or save it with 'print discover --save <number>'.")
+}
+
+// openConfig loads config.json from the config directory
+func openConfig() (*printer.Config, error) {
+	path, err := printer.DefaultConfigPath()
+	if err != nil {
+		return nil, err
+	}
+	return printer.LoadConfig(path)
+}
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// holdCmd represents the hold command
+var holdCmd = &cobra.Command{
+	Use:   "hold <job-id...>",
+	Short: "Hold pending print jobs",
+	Long: `Hold one or more pending print jobs so they are not printed until
+released with 'print release'.`,
+	Example: `  # Hold a job while loading A3+ paper
+  print hold 42
+
+  # Then release it
+  print release 42`,
+	Args: cobra.MinimumNArgs(1),
+	Run:  runHold,
+}
+
+func init() {
+	rootCmd.AddCommand(holdCmd)
+}
+
+func runHold(cmd *cobra.Command, args []string) {
+	requirePrinterURI()
+	client := newClient()
+
+	failed := 0
+	for _, jobID := range parseJobIDs(args) {
+		if err := client.HoldJob(cmd.Context(), jobID); err != nil {
+			fmt.Printf("✗ %v\n", err)
+			failed++
+			continue
+		}
+		fmt.Printf("✓ Held job %d\n", jobID)
+	}
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
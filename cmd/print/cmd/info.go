@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"log"
 
-	"github.com/Eric-Eklund/epson-printing/pkg/printer"
 	"github.com/spf13/cobra"
 )
 
@@ -17,7 +16,8 @@ and system details, then automatically print it.
 
 The report includes:
   - Printer name, model, and state
-  - All 6 ink tank levels with visual bars
+  - All ink tank levels with visual bars (6 on the ET-8550)
+  - Maintenance box level and status, when the printer reports it
   - Program information
   - Timestamp
 
@@ -31,23 +31,19 @@ func init() {
 	rootCmd.AddCommand(infoCmd)
 }
 
-func runInfo(_ *cobra.Command, _ []string) {
-	if printerURI == "" {
-		log.Fatal("Error: PRINTER_URI environment variable not set\n\n" +
-			"Please set the printer URI:\n" +
-			"  export PRINTER_URI=\"http://localhost:631/printers/EPSON_ET-8550_Series\"\n" +
-			"Or use --printer flag")
-	}
+func runInfo(cmd *cobra.Command, _ []string) {
+	requirePrinterURI()
 
 	fmt.Println("=============================================")
-	fmt.Println("Epson ET-8550 Status Report")
-	fmt.Println("=============================================\n")
+	fmt.Println("Printer Status Report")
+	fmt.Println("=============================================")
+	fmt.Println()
 
 	fmt.Printf("Fetching printer information from: %s\n", printerURI)
 	fmt.Println("Generating PDF report...")
 
 	// Generate and print the status report
-	pdfPath, jobID, err := printer.PrintStatusReport(printerURI)
+	pdfPath, jobID, err := newClient().PrintStatusReport(cmd.Context())
 	if err != nil {
 		log.Fatalf("Error: %v\n", err)
 	}
@@ -56,7 +52,8 @@ func runInfo(_ *cobra.Command, _ []string) {
 	fmt.Printf("✓ Print job sent! (Job ID: %d)\n", jobID)
 	fmt.Println("\nReport contains:")
 	fmt.Println("  - Printer information")
-	fmt.Println("  - Ink levels for all 6 tanks")
+	fmt.Println("  - Ink levels for all tanks")
+	fmt.Println("  - Maintenance box status")
 	fmt.Println("  - Program information")
 	fmt.Println("  - Date and time")
 	fmt.Println("\nPDF file saved for reference:", pdfPath)
@@ -0,0 +1,197 @@
+package cmd
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Eric-Eklund/epson-printing/pkg/printer"
+	"github.com/spf13/cobra"
+)
+
+// Ink history flags
+var inkSinceFlag string
+
+// inkCmd represents the ink command
+var inkCmd = &cobra.Command{
+	Use:   "ink",
+	Short: "Record and show ink level history",
+	Long: `Keep a history of ink levels to see how much ink each tank uses and
+when it will run empty.
+
+'print ink record' appends the current levels to ink-history.jsonl in the
+data directory ($XDG_DATA_HOME/epson-printing, usually
+~/.local/share/epson-printing). Run it regularly, e.g. from cron, and use
+'print ink history' to show consumption, refills and a forecast.`,
+	Example: `  # Record the current levels once a day at 9:00 (crontab -e)
+  0 9 * * * PRINTER_URI=ipp://EPSON6A3B2C.local/ipp/print print ink record
+
+  # Consumption over the last 30 days
+  print ink history --since 30d`,
+}
+
+// inkRecordCmd represents the ink record command
+var inkRecordCmd = &cobra.Command{
+	Use:   "record",
+	Short: "Save the current ink levels to the history",
+	Args:  cobra.NoArgs,
+	Run:   runInkRecord,
+}
+
+// inkHistoryCmd represents the ink history command
+var inkHistoryCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Show ink consumption, refills and when tanks run empty",
+	Long: `Show per-tank ink consumption over a period from the recorded history.
+A level that rises by 5% or more between two records counts as a refill;
+the forecast assumes the average consumption of the period continues.`,
+	Example: `  print ink history                # Last 30 days
+  print ink history --since 2w
+  print ink history --since 90d --json`,
+	Args: cobra.NoArgs,
+	Run:  runInkHistory,
+}
+
+func init() {
+	rootCmd.AddCommand(inkCmd)
+	inkCmd.AddCommand(inkRecordCmd, inkHistoryCmd)
+
+	inkHistoryCmd.Flags().StringVar(&inkSinceFlag, "since", "30d",
+		"Period to show: days (30d), weeks (2w) or a duration (12h)")
+	inkHistoryCmd.Flags().BoolVar(&jsonOutput, "json", false, "Output in JSON format")
+}
+
+func runInkRecord(cmd *cobra.Command, _ []string) {
+	requirePrinterURI()
+	history := openInkHistory()
+
+	info, err := newClient().Info(cmd.Context())
+	if err != nil {
+		log.Fatalf("Error: %v\n", err)
+	}
+
+	snapshot := printer.InkSnapshot{
+		Time:    time.Now(),
+		Printer: targetName(),
+		URI:     target.URI,
+		Model:   info.Model,
+		Levels:  info.InkLevels,
+	}
+	if err := history.Record(snapshot); err != nil {
+		log.Fatalf("Error: %v\n", err)
+	}
+
+	levels := make([]string, 0, len(info.InkLevels))
+	for _, ink := range info.InkLevels {
+		levels = append(levels, ink.Name+" "+levelText(ink.Level))
+	}
+	fmt.Printf("✓ Recorded ink levels of %s: %s\n", snapshot.Printer, strings.Join(levels, ", "))
+}
+
+func runInkHistory(_ *cobra.Command, _ []string) {
+	requirePrinterURI()
+	history := openInkHistory()
+
+	period, err := parseSince(inkSinceFlag)
+	if err != nil {
+		log.Fatalf("Error: --since: %v\n", err)
+	}
+
+	// Match by URI, so records made with --to, PRINTER_URI or the default
+	// printer all belong to the same printer
+	name := targetName()
+	snapshots, err := history.Snapshots(target.URI, time.Now().Add(-period))
+	if err != nil {
+		log.Fatalf("Error: %v\n", err)
+	}
+	usage := printer.InkUsageOf(snapshots)
+
+	if jsonOutput {
+		data, err := json.MarshalIndent(usage, "", "  ")
+		if err != nil {
+			log.Fatalf("Error: %v\n", err)
+		}
+		fmt.Println(string(data))
+		return
+	}
+
+	if len(snapshots) < 2 {
+		fmt.Printf("Not enough ink records for %s in the last %s (%d found).\n", name, inkSinceFlag, len(snapshots))
+		fmt.Println("Record levels regularly with 'print ink record', e.g. from cron.")
+		return
+	}
+
+	first, last := snapshots[0].Time, snapshots[len(snapshots)-1].Time
+	fmt.Printf("Ink usage of %s\n", name)
+	fmt.Printf("%s to %s (%d records)\n\n", first.Local().Format("2006-01-02"),
+		last.Local().Format("2006-01-02"), len(snapshots))
+
+	fmt.Printf("%-14s %6s %6s %6s %9s %8s  %s\n", "TANK", "START", "NOW", "USED", "PER WEEK", "REFILLS", "EMPTY IN")
+	for _, u := range usage {
+		emptyIn := "-"
+		if u.DaysLeft >= 0 {
+			emptyIn = fmt.Sprintf("~%.0f days (%s)", u.DaysLeft,
+				last.Add(time.Duration(u.DaysLeft*24)*time.Hour).Local().Format("2006-01-02"))
+		}
+		fmt.Printf("%-14.14s %6s %6s %5d%% %8.1f%% %8d  %s\n",
+			u.Name, levelText(u.First), levelText(u.Level), u.Used, u.PerDay*7, len(u.Refills), emptyIn)
+	}
+
+	var refills []string
+	for _, u := range usage {
+		for _, r := range u.Refills {
+			refills = append(refills, fmt.Sprintf("  %s  %-14s %3d%% → %3d%%",
+				r.Time.Local().Format("2006-01-02 15:04"), u.Name, r.From, r.To))
+		}
+	}
+	if len(refills) > 0 {
+		fmt.Println("\nRefills:")
+		fmt.Println(strings.Join(refills, "\n"))
+	}
+}
+
+// openInkHistory returns the ink history in the data directory
+func openInkHistory() *printer.InkHistory {
+	path, err := printer.DefaultInkHistoryPath()
+	if err != nil {
+		log.Fatalf("Error: %v\n", err)
+	}
+	return printer.NewInkHistory(path)
+}
+
+// targetName names the selected printer in the ink history and alerts: its
+// registry name, or its URI
+func targetName() string {
+	return cmp.Or(target.Name, target.URI)
+}
+
+// levelText formats an ink level, or "?" when the printer does not know it
+func levelText(level int) string {
+	if level < 0 {
+		return "?"
+	}
+	return strconv.Itoa(level) + "%"
+}
+
+// parseSince parses a period like "30d", "2w" or a Go duration like "12h"
+func parseSince(s string) (time.Duration, error) {
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if n, ok := strings.CutSuffix(s, suffix); ok {
+			count, err := strconv.Atoi(n)
+			if err != nil || count <= 0 {
+				return 0, fmt.Errorf("invalid period %q (use e.g. 30d, 2w or 12h)", s)
+			}
+			return time.Duration(count) * unit, nil
+		}
+	}
+
+	period, err := time.ParseDuration(s)
+	if err != nil || period <= 0 {
+		return 0, fmt.Errorf("invalid period %q (use e.g. 30d, 2w or 12h)", s)
+	}
+	return period, nil
+}
@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/Eric-Eklund/epson-printing/pkg/printer"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Job command flags
+	waitFlag     bool
+	intervalFlag time.Duration
+)
+
+// jobCmd represents the job command
+var jobCmd = &cobra.Command{
+	Use:     "job <job-id>",
+	Aliases: []string{"status"},
+	Short:   "Show the status of a print job",
+	Long: `Show the status of a print job: state, state reasons, impressions
+completed and creation/processing/completion times.
+
+Use --wait to poll until the job is completed, canceled or aborted. The
+command exits with a non-zero status if the job was canceled or aborted.`,
+	Example: `  # Show job status
+  print job 42
+
+  # Wait until the job has finished printing
+  print job 42 --wait
+
+  # Output in JSON format
+  print job 42 --json`,
+	Args: cobra.ExactArgs(1),
+	Run:  runJob,
+}
+
+func init() {
+	rootCmd.AddCommand(jobCmd)
+	jobCmd.Flags().BoolVar(&jsonOutput, "json", false, "Output in JSON format")
+	jobCmd.Flags().BoolVar(&waitFlag, "wait", false,
+		"Wait until the job reaches a final state")
+	jobCmd.Flags().DurationVar(&intervalFlag, "interval", printer.DefaultPollInterval,
+		"Polling interval for --wait")
+}
+
+func runJob(cmd *cobra.Command, args []string) {
+	requirePrinterURI()
+
+	jobID := parseJobIDs(args)[0]
+
+	client := newClient()
+	var job *printer.Job
+	var err error
+	if waitFlag {
+		job = waitForJob(cmd, client, jobID)
+	} else {
+		job, err = client.GetJob(cmd.Context(), jobID)
+		if err != nil {
+			log.Fatalf("Error: %v\n", err)
+		}
+	}
+
+	if jsonOutput {
+		jsonStr, err := job.ToJSON()
+		if err != nil {
+			log.Fatalf("Error: Failed to generate JSON\n%v\n", err)
+		}
+		fmt.Println(jsonStr)
+	} else {
+		job.Print()
+	}
+
+	if job.State == printer.JobCanceled || job.State == printer.JobAborted {
+		os.Exit(1)
+	}
+}
+
+// waitForJob polls a job until it reaches a final state, reporting progress
+func waitForJob(cmd *cobra.Command, client *printer.Client, jobID int) *printer.Job {
+	if !jsonOutput {
+		fmt.Printf("Waiting for job %d to finish...\n", jobID)
+	}
+
+	job, err := client.WaitJob(cmd.Context(), jobID, intervalFlag)
+	if err != nil {
+		log.Fatalf("Error: %v\n", err)
+	}
+	return job
+}
+
+// parseJobIDs converts job ID arguments to integers, exiting on invalid input
+func parseJobIDs(args []string) []int {
+	ids := make([]int, 0, len(args))
+	for _, arg := range args {
+		id, err := strconv.Atoi(arg)
+		if err != nil || id <= 0 {
+			log.Fatalf("Error: invalid job ID: %s\n", arg)
+		}
+		ids = append(ids, id)
+	}
+	return ids
+}
@@ -2,11 +2,21 @@ package cmd
 
 import (
 	"fmt"
+	"log"
+	"math"
+	"os"
+	"slices"
 
 	"github.com/Eric-Eklund/epson-printing/pkg/printer"
 	"github.com/spf13/cobra"
 )
 
+var (
+	// List flags
+	listModelFlag string
+	listAllFlag   bool
+)
+
 // listCmd represents the list command
 var listCmd = &cobra.Command{
 	Use:   "list",
@@ -14,10 +24,18 @@ var listCmd = &cobra.Command{
 	Long: `Display all available print profiles with their IDs, names, and settings.
 
 Use profile IDs or names with the print command to quickly select
-paper size, quality, and media type combinations.`,
+paper size, quality, and media type combinations.
+
+Profiles the printer model cannot use, such as A3+ on models that do not
+feed 13x19" paper, are hidden. The model is taken from --model, from the
+model saved for the printer in the registry, or else asked from the
+selected printer (--to, --printer, PRINTER_URI or the default printer).`,
 	Example: `  # List all profiles
   print list
 
+  # Profiles for an ET-2850
+  print list --model ET-2850
+
   # Then print using a profile ID
   print document.pdf 14`,
 	Run: runList,
@@ -25,13 +43,34 @@ paper size, quality, and media type combinations.`,
 
 func init() {
 	rootCmd.AddCommand(listCmd)
+
+	listCmd.Flags().StringVar(&listModelFlag, "model", "",
+		"Printer model to list profiles for, e.g. ET-2850")
+	listCmd.Flags().BoolVar(&listAllFlag, "all", false,
+		"Also list profiles the printer model cannot use")
 }
 
-func runList(_ *cobra.Command, _ []string) {
-	fmt.Println("Available Print Profiles:")
-	fmt.Println("========================\n")
+func runList(cmd *cobra.Command, _ []string) {
+	model := listModel(cmd)
+	if model.IsGeneric() {
+		fmt.Println("Available Print Profiles:")
+	} else {
+		fmt.Printf("Available Print Profiles (%s):\n", model.Name)
+	}
+	fmt.Println("========================")
+	fmt.Println()
 
 	infos := printer.ListProfilesWithInfo()
+	hidden := 0
+	if !listAllFlag {
+		infos = slices.DeleteFunc(infos, func(info printer.ProfileInfo) bool {
+			if model.SupportsProfile(info.Name) {
+				return false
+			}
+			hidden++
+			return true
+		})
+	}
 
 	// Group by category
 	categories := []struct {
@@ -46,9 +85,17 @@ func runList(_ *cobra.Command, _ []string) {
 		{"A3 Borderless", 10, 12},
 		{"A3+ Borderless (13x19\")", 13, 15},
 		{"Documents", 16, 18},
+		{"Custom", printer.MaxBuiltinProfileID + 1, math.MaxInt},
 	}
 
 	for _, cat := range categories {
+		matches := slices.ContainsFunc(infos, func(info printer.ProfileInfo) bool {
+			return info.ID >= cat.idStart && info.ID <= cat.idEnd
+		})
+		if !matches {
+			continue // No user profiles saved yet
+		}
+
 		if cat.name != "Default" {
 			fmt.Println()
 		}
@@ -56,17 +103,25 @@ func runList(_ *cobra.Command, _ []string) {
 
 		for _, info := range infos {
 			if info.ID >= cat.idStart && info.ID <= cat.idEnd {
-				fmt.Printf("  %-2d  %-35s  %s, %s, %s, Quality %d\n",
+				fmt.Printf("  %-2d  %-35s  %s, %s, %s, Quality %d",
 					info.ID,
 					info.Name,
 					info.PaperSize,
 					info.Tray,
 					formatMediaType(info.MediaType),
 					info.Quality)
+				if info.Extends != "" {
+					fmt.Printf(" (extends %s)", info.Extends)
+				}
+				fmt.Println()
 			}
 		}
 	}
 
+	if hidden > 0 {
+		fmt.Printf("\n%d profile(s) hidden: not supported by the %s (use --all to show them)\n", hidden, model.Name)
+	}
+
 	fmt.Println("\nUsage:")
 	fmt.Println("  print <file> <profile-id-or-name>")
 	fmt.Println("\nExamples:")
@@ -74,6 +129,44 @@ func runList(_ *cobra.Command, _ []string) {
 	fmt.Println("  print photo.jpg photo-4x6-borderless-glossy         # Use full name")
 	fmt.Println("  print document.pdf 14 --pages \"2:\"                  # Pages 2 to end")
 	fmt.Println("  print calendar.pdf 7 --quality 3 --pages \"1-5\"      # Override settings")
+	fmt.Println("\nSave your own profiles with 'print profile add'.")
+}
+
+// listModel returns the model given with --model, or the model of the
+// selected printer: the one saved in the registry, or else the one the
+// printer reports. Without a printer, or when it cannot be reached, the
+// generic model shows every profile.
+func listModel(cmd *cobra.Command) printer.Model {
+	if listModelFlag != "" {
+		model, err := printer.LookupModel(listModelFlag)
+		if err != nil {
+			log.Fatalf("Error: --model: %v\n", err)
+		}
+		return model
+	}
+
+	entry, err := selectPrinter()
+	if err != nil {
+		log.Fatalf("Error: %v\n", err)
+	}
+	if entry.URI == "" {
+		return printer.GenericModel
+	}
+	if entry.Model != "" {
+		if model, err := printer.LookupModel(entry.Model); err == nil {
+			return model
+		}
+	}
+
+	if tlsOpts != (printer.TLSOptions{}) {
+		entry.TLS = tlsOpts
+	}
+	model, err := queryModel(cmd, entry)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not detect the printer model (%v); showing profiles for all models\n", err)
+		return printer.GenericModel
+	}
+	return model
 }
 
 func formatMediaType(media string) string {
@@ -0,0 +1,296 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Eric-Eklund/epson-printing/pkg/printer"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Printers add flags
+	printerProfileFlag  string
+	printerUserFlag     string
+	printerPasswordFlag bool
+	printerDefaultFlag  bool
+	printerModelFlag    string
+)
+
+// printersCmd represents the printers command
+var printersCmd = &cobra.Command{
+	Use:   "printers",
+	Short: "Manage named printers",
+	Long: `Add, list and remove named printers, so commands can select one with
+--to <name> instead of a printer URI.
+
+Each printer has a URI and optionally a default profile, credentials for
+HTTP basic auth (e.g. a CUPS server) and TLS settings. Printers are saved in
+config.json in the config directory ($XDG_CONFIG_HOME/epson-printing,
+usually ~/.config/epson-printing), which is only readable by you. Passwords
+are stored there in cleartext.
+
+Without --to or --printer, commands use the default printer. PRINTER_URI
+still takes precedence over the default printer.`,
+	Example: `  # Register two printers
+  print printers add photo ipps://EPSON6A3B2C.local/ipp/print --profile 14 --insecure
+  print printers add office ipp://EPSON8D1E2F.local/ipp/print --profile document-normal
+
+  # Print to one of them
+  print report.pdf --to office
+
+  # Make the office printer the default
+  print printers default office`,
+}
+
+// printersAddCmd represents the printers add command
+var printersAddCmd = &cobra.Command{
+	Use:   "add <name> <uri>",
+	Short: "Add a named printer",
+	Long: `Add a named printer. The TLS flags (--ca-cert, --insecure,
+--client-cert, --client-key) given with this command are saved with the
+printer. The first printer added becomes the default.
+
+The basic auth password is read from stdin with --password-stdin, or from
+the PRINTER_PASSWORD environment variable, so it does not end up in the
+shell history or the process list. It is saved in cleartext in config.json.
+
+The printer model (ET-8550, ET-8500 or ET-2850) is asked from the printer
+unless given with --model; 'print list' uses it to hide profiles the model
+cannot print.`,
+	Example: `  # A CUPS queue that requires a login, with the password from a file
+  print printers add cups http://print-server:631/printers/ET-2850 --user anna \
+    --password-stdin < ~/.cups-password`,
+	Args: cobra.ExactArgs(2),
+	Run:  runPrintersAdd,
+}
+
+// printersListCmd represents the printers list command
+var printersListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List named printers",
+	Args:    cobra.NoArgs,
+	Run:     runPrintersList,
+}
+
+// printersRemoveCmd represents the printers remove command
+var printersRemoveCmd = &cobra.Command{
+	Use:     "remove <name>",
+	Aliases: []string{"rm"},
+	Short:   "Remove a named printer",
+	Args:    cobra.ExactArgs(1),
+	Run:     runPrintersRemove,
+}
+
+// printersDefaultCmd represents the printers default command
+var printersDefaultCmd = &cobra.Command{
+	Use:   "default <name>",
+	Short: "Set the default printer",
+	Args:  cobra.ExactArgs(1),
+	Run:   runPrintersDefault,
+}
+
+func init() {
+	rootCmd.AddCommand(printersCmd)
+	printersCmd.AddCommand(printersAddCmd, printersListCmd, printersRemoveCmd, printersDefaultCmd)
+
+	printersAddCmd.Flags().StringVarP(&printerProfileFlag, "profile", "p", "",
+		"Default profile name or ID for this printer")
+	printersAddCmd.Flags().StringVar(&printerUserFlag, "user", "",
+		"User name for HTTP basic auth and job ownership")
+	printersAddCmd.Flags().BoolVar(&printerPasswordFlag, "password-stdin", false,
+		"Read the password for HTTP basic auth from stdin (default from PRINTER_PASSWORD env var)")
+	printersAddCmd.Flags().BoolVar(&printerDefaultFlag, "default", false,
+		"Make this the default printer")
+	printersAddCmd.Flags().StringVar(&printerModelFlag, "model", "",
+		"Printer model, e.g. ET-2850 (default: detected from the printer)")
+}
+
+func runPrintersAdd(cmd *cobra.Command, args []string) {
+	cfg, err := openConfig()
+	if err != nil {
+		log.Fatalf("Error: %v\n", err)
+	}
+
+	entry := printer.PrinterEntry{
+		Name:     args[0],
+		URI:      args[1],
+		Username: printerUserFlag,
+		Password: readPrinterPassword(),
+		TLS:      tlsOpts,
+	}
+	if printerProfileFlag != "" {
+		profile, err := resolveProfile(printerProfileFlag)
+		if err != nil {
+			log.Fatalf("Error: --profile: %v\n", err)
+		}
+		if _, err := printer.GetPrintOptions(profile); err != nil {
+			log.Fatalf("Error: --profile: %v\n", err)
+		}
+		entry.Profile = string(profile)
+	}
+	if printerModelFlag != "" {
+		model, err := printer.LookupModel(printerModelFlag)
+		if err != nil {
+			log.Fatalf("Error: --model: %v\n", err)
+		}
+		entry.Model = model.Name
+	} else {
+		entry.Model = detectModel(cmd, entry)
+	}
+
+	if err := cfg.AddPrinter(entry); err != nil {
+		log.Fatalf("Error: %v\n", err)
+	}
+	if printerDefaultFlag {
+		_ = cfg.SetDefaultPrinter(entry.Name)
+	}
+	if err := cfg.Save(); err != nil {
+		log.Fatalf("Error: %v\n", err)
+	}
+
+	fmt.Printf("✓ Added printer %s (%s)\n", entry.Name, entry.URI)
+	if entry.Model != "" {
+		fmt.Printf("✓ Model: %s\n", entry.Model)
+	}
+	if strings.EqualFold(cfg.DefaultPrinter, entry.Name) {
+		fmt.Printf("✓ %s is the default printer\n", entry.Name)
+	}
+	if entry.Password != "" {
+		fmt.Printf("Note: the password is stored in cleartext in %s\n", cfg.Path)
+	}
+}
+
+// readPrinterPassword returns the basic auth password from the first line
+// of stdin with --password-stdin, or from PRINTER_PASSWORD
+func readPrinterPassword() string {
+	if !printerPasswordFlag {
+		return os.Getenv("PRINTER_PASSWORD")
+	}
+
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && err != io.EOF {
+		log.Fatalf("Error: reading password from stdin: %v\n", err)
+	}
+	password := strings.TrimRight(line, "\r\n")
+	if password == "" {
+		log.Fatalf("Error: --password-stdin: no password on stdin\n")
+	}
+	return password
+}
+
+func runPrintersList(_ *cobra.Command, _ []string) {
+	cfg, err := openConfig()
+	if err != nil {
+		log.Fatalf("Error: %v\n", err)
+	}
+	if len(cfg.Printers) == 0 {
+		fmt.Println("No printers added yet. Use 'print printers add <name> <uri>'.")
+		return
+	}
+
+	fmt.Printf("  %-12s  %-45s  %-14s  %-30s  %s\n", "NAME", "URI", "MODEL", "PROFILE", "AUTH")
+	for _, p := range cfg.Printers {
+		marker := " "
+		if strings.EqualFold(p.Name, cfg.DefaultPrinter) {
+			marker = "*"
+		}
+		fmt.Printf("%s %-12s  %-45s  %-14s  %-30s  %s\n", marker, p.Name, p.URI, p.Model, p.Profile, describeAuth(p))
+	}
+	if cfg.DefaultPrinter != "" {
+		fmt.Println("\n* default printer")
+	}
+}
+
+// detectModel asks the printer for its make and model and returns the name
+// of the matching built-in model, or "" when the printer cannot be reached
+// or is not a built-in model
+func detectModel(cmd *cobra.Command, entry printer.PrinterEntry) string {
+	model, err := queryModel(cmd, entry)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not detect the printer model (%v); use --model to set it\n", err)
+		return ""
+	}
+	if !model.IsGeneric() {
+		return model.Name
+	}
+	return ""
+}
+
+// queryModel asks the printer for its make and model with a short timeout
+// and returns the matching model, GenericModel for unknown printers
+func queryModel(cmd *cobra.Command, entry printer.PrinterEntry) (printer.Model, error) {
+	client, err := printer.NewClientFor(entry)
+	if err != nil {
+		log.Fatalf("Error: %v\n", err)
+	}
+	client.Timeout = 5 * time.Second
+
+	caps, err := client.Capabilities(cmd.Context())
+	if err != nil {
+		return printer.GenericModel, err
+	}
+	return caps.Model(), nil
+}
+
+// describeAuth summarizes the credentials and TLS settings of a printer
+// without showing the password
+func describeAuth(p printer.PrinterEntry) string {
+	var parts []string
+	if p.Username != "" {
+		user := p.Username
+		if p.Password != "" {
+			user += " (password)"
+		}
+		parts = append(parts, user)
+	}
+	if p.TLS.InsecureSkipVerify {
+		parts = append(parts, "insecure TLS")
+	}
+	if p.TLS.CAFile != "" {
+		parts = append(parts, "CA "+p.TLS.CAFile)
+	}
+	if p.TLS.CertFile != "" {
+		parts = append(parts, "client cert")
+	}
+	return strings.Join(parts, ", ")
+}
+
+func runPrintersRemove(_ *cobra.Command, args []string) {
+	cfg, err := openConfig()
+	if err != nil {
+		log.Fatalf("Error: %v\n", err)
+	}
+	if err := cfg.RemovePrinter(args[0]); err != nil {
+		log.Fatalf("Error: %v\n", err)
+	}
+	if err := cfg.Save(); err != nil {
+		log.Fatalf("Error: %v\n", err)
+	}
+
+	fmt.Printf("✓ Removed printer %s\n", args[0])
+	if cfg.DefaultPrinter == "" && len(cfg.Printers) > 0 {
+		fmt.Println("No default printer set; use 'print printers default <name>'")
+	}
+}
+
+func runPrintersDefault(_ *cobra.Command, args []string) {
+	cfg, err := openConfig()
+	if err != nil {
+		log.Fatalf("Error: %v\n", err)
+	}
+	if err := cfg.SetDefaultPrinter(args[0]); err != nil {
+		log.Fatalf("Error: %v\n", err)
+	}
+	if err := cfg.Save(); err != nil {
+		log.Fatalf("Error: %v\n", err)
+	}
+
+	fmt.Printf("✓ %s is the default printer\n", cfg.DefaultPrinter)
+}
@@ -0,0 +1,278 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/Eric-Eklund/epson-printing/pkg/printer"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Profile add/edit flags
+	extendsFlag string
+
+	// Profile export flags
+	exportOutputFlag string
+)
+
+// profileCmd represents the profile command
+var profileCmd = &cobra.Command{
+	Use:   "profile",
+	Short: "Manage user-defined print profiles",
+	Long: `Create, change and remove your own print profiles.
+
+User profiles are saved in profiles.json in the config directory
+($XDG_CONFIG_HOME/epson-printing, usually ~/.config/epson-printing) and
+loaded at startup next to the built-in profiles. They get stable numeric
+IDs from 19 upwards and can be used anywhere a profile is accepted.`,
+	Example: `  # Save a preset for calendar pages
+  print profile add calendar --extends 14 --pages "1-12"
+
+  # Use it like any other profile
+  print calendar.pdf calendar`,
+}
+
+// profileAddCmd represents the profile add command
+var profileAddCmd = &cobra.Command{
+	Use:   "add <name>",
+	Short: "Create a user profile",
+	Long: `Create a user profile that extends an existing profile (--extends,
+default: the default profile) and overrides only the given settings.
+Settings that are not given are inherited, so later changes to the base
+profile carry over.`,
+	Example: `  # A4 glossy photos from the rear tray
+  print profile add a4-glossy-rear --extends photo-a4-borderless-glossy --tray Rear
+
+  # Two copies of every page in normal quality
+  print profile add handout --quality 4 --copies 2`,
+	Args: cobra.ExactArgs(1),
+	Run:  runProfileAdd,
+}
+
+// profileEditCmd represents the profile edit command
+var profileEditCmd = &cobra.Command{
+	Use:   "edit <name>",
+	Short: "Change settings of a user profile",
+	Example: `  # Switch a profile to matte paper
+  print profile edit a4-glossy-rear --media photographic-matte`,
+	Args: cobra.ExactArgs(1),
+	Run:  runProfileEdit,
+}
+
+// profileRemoveCmd represents the profile remove command
+var profileRemoveCmd = &cobra.Command{
+	Use:     "remove <name>",
+	Aliases: []string{"rm"},
+	Short:   "Remove a user profile",
+	Args:    cobra.ExactArgs(1),
+	Run:     runProfileRemove,
+}
+
+// profileShowCmd represents the profile show command
+var profileShowCmd = &cobra.Command{
+	Use:   "show <profile>",
+	Short: "Show the settings of a profile",
+	Long:  `Show the settings of a built-in or user profile, by name or ID.`,
+	Args:  cobra.ExactArgs(1),
+	Run:   runProfileShow,
+}
+
+// profileExportCmd represents the profile export command
+var profileExportCmd = &cobra.Command{
+	Use:   "export [name...]",
+	Short: "Export user profiles as JSON",
+	Long: `Write user profiles, or only the named ones, as JSON in the
+profiles.json format, e.g. to copy them to another machine.`,
+	Example: `  # Export all user profiles
+  print profile export -o my-profiles.json`,
+	Run: runProfileExport,
+}
+
+func init() {
+	rootCmd.AddCommand(profileCmd)
+	profileCmd.AddCommand(profileAddCmd, profileEditCmd, profileRemoveCmd,
+		profileShowCmd, profileExportCmd)
+
+	profileAddCmd.Flags().StringVar(&extendsFlag, "extends", "default",
+		"Profile name or ID to inherit unset settings from (\"\" for none)")
+	profileEditCmd.Flags().StringVar(&extendsFlag, "extends", "",
+		"Change the profile to inherit settings from")
+	addSettingFlags(profileAddCmd)
+	addSettingFlags(profileEditCmd)
+
+	profileExportCmd.Flags().StringVarP(&exportOutputFlag, "output", "o", "",
+		"Write to file instead of stdout")
+
+	cobra.OnInitialize(loadUserProfiles)
+}
+
+// loadUserProfiles registers the saved user profiles with the built-in ones.
+// A broken profiles file or profile is reported but does not stop other
+// commands.
+func loadUserProfiles() {
+	store, err := openProfileStore()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: user profiles not loaded: %v\n", err)
+		return
+	}
+	for _, err := range store.Problems() {
+		fmt.Fprintf(os.Stderr, "Warning: %v (skipped; fix it with 'print profile edit' or remove it)\n", err)
+	}
+	store.Register()
+}
+
+// openProfileStore loads the user profile store from the config directory
+func openProfileStore() (*printer.ProfileStore, error) {
+	path, err := printer.DefaultProfilesPath()
+	if err != nil {
+		return nil, err
+	}
+	return printer.LoadProfileStore(path)
+}
+
+func runProfileAdd(_ *cobra.Command, args []string) {
+	store, err := openProfileStore()
+	if err != nil {
+		log.Fatalf("Error: %v\n", err)
+	}
+
+	base := resolveBaseProfile(extendsFlag)
+	profile, err := store.Add(printer.PrintProfile(args[0]), base, flagOverlay())
+	if err != nil {
+		log.Fatalf("Error: %v\n", err)
+	}
+	if err := store.Save(); err != nil {
+		log.Fatalf("Error: %v\n", err)
+	}
+
+	opts, _ := store.Resolve(profile.Name)
+	fmt.Printf("✓ Added profile %s (ID %d): %s\n", profile.Name, profile.ID, describeOptions(opts))
+}
+
+func runProfileEdit(cmd *cobra.Command, args []string) {
+	store, err := openProfileStore()
+	if err != nil {
+		log.Fatalf("Error: %v\n", err)
+	}
+
+	name := printer.PrintProfile(args[0])
+	profile, ok := store.Get(name)
+	if !ok {
+		if printer.IsBuiltinProfile(name) {
+			log.Fatalf("Error: %s is a built-in profile; use 'print profile add <name> --extends %s' to customize it\n", name, name)
+		}
+		log.Fatalf("Error: unknown user profile: %s\n", name)
+	}
+
+	base := profile.Extends
+	if cmd.Flags().Changed("extends") {
+		base = resolveBaseProfile(extendsFlag)
+	}
+	overrides := profile.PrintOptions.Overlay(flagOverlay())
+	if err := store.Update(name, base, overrides); err != nil {
+		log.Fatalf("Error: %v\n", err)
+	}
+	if err := store.Save(); err != nil {
+		log.Fatalf("Error: %v\n", err)
+	}
+
+	opts, _ := store.Resolve(name)
+	fmt.Printf("✓ Updated profile %s (ID %d): %s\n", name, profile.ID, describeOptions(opts))
+}
+
+// resolveBaseProfile turns an --extends value (name or ID) into a profile name
+func resolveBaseProfile(base string) printer.PrintProfile {
+	if base == "" {
+		return ""
+	}
+	name, err := resolveProfile(base)
+	if err != nil {
+		log.Fatalf("Error: --extends: %v\n", err)
+	}
+	return name
+}
+
+func runProfileRemove(_ *cobra.Command, args []string) {
+	store, err := openProfileStore()
+	if err != nil {
+		log.Fatalf("Error: %v\n", err)
+	}
+
+	name := printer.PrintProfile(args[0])
+	if printer.IsBuiltinProfile(name) {
+		log.Fatalf("Error: %s is a built-in profile and cannot be removed\n", name)
+	}
+	if err := store.Remove(name); err != nil {
+		log.Fatalf("Error: %v\n", err)
+	}
+	if err := store.Save(); err != nil {
+		log.Fatalf("Error: %v\n", err)
+	}
+
+	fmt.Printf("✓ Removed profile %s\n", name)
+}
+
+func runProfileShow(_ *cobra.Command, args []string) {
+	name, err := resolveProfile(args[0])
+	if err != nil {
+		log.Fatalf("Error: %v\n", err)
+	}
+	opts, err := printer.GetPrintOptions(name)
+	if err != nil {
+		log.Fatalf("Error: %v\n", err)
+	}
+	id := printer.GetProfileID(name)
+
+	kind := "built-in"
+	if !printer.IsBuiltinProfile(name) {
+		kind = "user"
+	}
+
+	fmt.Printf("Profile:     %s (ID %d, %s)\n", name, id, kind)
+	if store, err := openProfileStore(); err == nil {
+		if profile, ok := store.Get(name); ok && profile.Extends != "" {
+			fmt.Printf("Extends:     %s\n", profile.Extends)
+		}
+	}
+	fmt.Printf("Paper size:  %s\n", opts.PaperSize)
+	fmt.Printf("Tray:        %s\n", opts.Tray)
+	fmt.Printf("Media type:  %s\n", opts.MediaType)
+	fmt.Printf("Quality:     %d (3=draft, 4=normal, 5=best)\n", opts.Quality)
+	fmt.Printf("Pages:       %s\n", opts.PageRange)
+	fmt.Printf("Copies:      %d\n", opts.Copies)
+}
+
+func runProfileExport(_ *cobra.Command, args []string) {
+	store, err := openProfileStore()
+	if err != nil {
+		log.Fatalf("Error: %v\n", err)
+	}
+
+	names := make([]printer.PrintProfile, 0, len(args))
+	for _, arg := range args {
+		names = append(names, printer.PrintProfile(arg))
+	}
+
+	out := os.Stdout
+	if exportOutputFlag != "" {
+		out, err = os.Create(exportOutputFlag)
+		if err != nil {
+			log.Fatalf("Error: %v\n", err)
+		}
+		defer func() {
+			_ = out.Close()
+		}()
+	}
+
+	if err := store.Export(out, names...); err != nil {
+		log.Fatalf("Error: %v\n", err)
+	}
+}
+
+// describeOptions summarizes print options on one line
+func describeOptions(opts printer.PrintOptions) string {
+	return fmt.Sprintf("%s on %s (%s, quality: %d)",
+		opts.PaperSize, opts.MediaType, opts.Tray, opts.Quality)
+}
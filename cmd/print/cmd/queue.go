@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/Eric-Eklund/epson-printing/pkg/printer"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Queue command flags
+	completedFlag bool
+	mineFlag      bool
+	limitFlag     int
+)
+
+// queueCmd represents the queue command
+var queueCmd = &cobra.Command{
+	Use:   "queue",
+	Short: "List print jobs on the printer",
+	Long: `List the jobs queued on the printer with their owner, name, state,
+pages printed and creation time.
+
+By default only jobs that have not completed yet are shown. Use --completed
+to see finished jobs instead and --mine to only show your own jobs.`,
+	Example: `  # Show the current queue
+  print queue
+
+  # Show the last 10 completed jobs
+  print queue --completed --limit 10
+
+  # Only my jobs, as JSON
+  print queue --mine --json`,
+	Run: runQueue,
+}
+
+func init() {
+	rootCmd.AddCommand(queueCmd)
+	queueCmd.Flags().BoolVar(&jsonOutput, "json", false, "Output in JSON format")
+	queueCmd.Flags().BoolVar(&completedFlag, "completed", false,
+		"Show completed jobs instead of the queue")
+	queueCmd.Flags().BoolVar(&mineFlag, "mine", false, "Only show your own jobs")
+	queueCmd.Flags().IntVar(&limitFlag, "limit", 0, "Maximum number of jobs to show (0 = all)")
+}
+
+func runQueue(cmd *cobra.Command, _ []string) {
+	requirePrinterURI()
+
+	which := printer.WhichJobsNotCompleted
+	if completedFlag {
+		which = printer.WhichJobsCompleted
+	}
+
+	jobs, err := newClient().GetJobs(cmd.Context(), which, mineFlag, limitFlag)
+	if err != nil {
+		log.Fatalf("Error: %v\n", err)
+	}
+
+	if jsonOutput {
+		jsonStr, err := jobs.ToJSON()
+		if err != nil {
+			log.Fatalf("Error: Failed to generate JSON\n%v\n", err)
+		}
+		fmt.Println(jsonStr)
+		return
+	}
+
+	jobs.Print()
+}
@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// releaseCmd represents the release command
+var releaseCmd = &cobra.Command{
+	Use:   "release <job-id...>",
+	Short: "Release held print jobs",
+	Long:  `Release one or more print jobs previously held with 'print hold'.`,
+	Example: `  # Release a held job
+  print release 42`,
+	Args: cobra.MinimumNArgs(1),
+	Run:  runRelease,
+}
+
+func init() {
+	rootCmd.AddCommand(releaseCmd)
+}
+
+func runRelease(cmd *cobra.Command, args []string) {
+	requirePrinterURI()
+	client := newClient()
+
+	failed := 0
+	for _, jobID := range parseJobIDs(args) {
+		if err := client.ReleaseJob(cmd.Context(), jobID); err != nil {
+			fmt.Printf("✗ %v\n", err)
+			failed++
+			continue
+		}
+		fmt.Printf("✓ Released job %d\n", jobID)
+	}
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
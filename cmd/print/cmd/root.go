@@ -1,10 +1,14 @@
 package cmd
 
 import (
+	"cmp"
+	"context"
 	"fmt"
 	"log"
 	"os"
+	"os/signal"
 	"strconv"
+	"time"
 
 	"github.com/Eric-Eklund/epson-printing/pkg/printer"
 	"github.com/spf13/cobra"
@@ -12,7 +16,12 @@ import (
 
 var (
 	// Persistent flags (available to all commands)
-	printerURI string
+	printerURI  string
+	toFlag      string
+	timeoutFlag time.Duration
+	tlsOpts     printer.TLSOptions
+	legacyCUPS  bool
+	retriesFlag int
 
 	// Print command flags
 	profileFlag string
@@ -21,6 +30,12 @@ var (
 	paperFlag   string
 	trayFlag    string
 	mediaFlag   string
+	copiesFlag  int
+	dryRunFlag  bool
+	dumpIPPFlag string
+
+	// target is the printer selected by requirePrinterURI
+	target printer.PrinterEntry
 )
 
 // rootCmd represents the base command when called without any subcommands
@@ -28,14 +43,16 @@ var rootCmd = &cobra.Command{
 	Use:   "print <file> [profile]",
 	Short: "Print files with profile-based settings",
 	Long: `Print files (PDF, images, documents) to your Epson ET-8550 printer
-using predefined profiles or custom settings.
+using predefined profiles or custom settings. The ET-8500, ET-2850 and other
+IPP printers work too; settings are checked against the printer model.
 
 Profile can be specified as:
-  - Numeric ID (0-18): print file.pdf 14
+  - Numeric ID (0-18, 19+ for your own profiles): print file.pdf 14
   - Profile name: print file.pdf photo-a3plus-borderless-matte
   - Default (0) if not specified
 
-Use 'print list' to see all available profiles.`,
+Use 'print list' to see all available profiles and 'print profile add'
+to save your own.`,
 	Example: `  # Print with profile ID
   print document.pdf 14
   print photo.jpg 1
@@ -45,14 +62,25 @@ Use 'print list' to see all available profiles.`,
 
   # Override settings
   print document.pdf 14 --pages "2:"
-  print document.pdf 7 --quality 3`,
+  print document.pdf 14 --pages "1-3,7,10:"
+  print document.pdf 7 --quality 3
+
+  # Wait for the job to finish printing
+  print photo.jpg 1 --wait
+
+  # Inspect the IPP request without printing
+  print photo.jpg 1 --dry-run --dump-ipp request.bin`,
 	Args: cobra.MinimumNArgs(1),
 	Run:  runPrint,
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
+// Interrupting the process (Ctrl+C) cancels any in-flight printer request.
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if err := rootCmd.ExecuteContext(ctx); err != nil {
 		os.Exit(1)
 	}
 }
@@ -61,33 +89,43 @@ func init() {
 	// Persistent flags (available to all subcommands)
 	rootCmd.PersistentFlags().StringVar(&printerURI, "printer", os.Getenv("PRINTER_URI"),
 		"Printer URI (default from PRINTER_URI env var)")
+	rootCmd.PersistentFlags().StringVar(&toFlag, "to", "",
+		"Name of a printer added with 'print printers add'")
+	rootCmd.MarkFlagsMutuallyExclusive("printer", "to")
+	rootCmd.PersistentFlags().DurationVar(&timeoutFlag, "timeout", printer.DefaultTimeout,
+		"Timeout for each printer request; restarts while a document uploads (0 disables)")
+	rootCmd.PersistentFlags().StringVar(&tlsOpts.CAFile, "ca-cert", "",
+		"PEM file with CA certificates to trust for ipps:// printers")
+	rootCmd.PersistentFlags().BoolVar(&tlsOpts.InsecureSkipVerify, "insecure", false,
+		"Skip TLS certificate verification (self-signed printer certificates)")
+	rootCmd.PersistentFlags().StringVar(&tlsOpts.CertFile, "client-cert", "",
+		"PEM client certificate for mutual TLS")
+	rootCmd.PersistentFlags().StringVar(&tlsOpts.KeyFile, "client-key", "",
+		"PEM private key for --client-cert")
+	rootCmd.PersistentFlags().BoolVar(&legacyCUPS, "legacy-cups", false,
+		"Send CUPS PPD job attributes (PageSize, InputSlot) instead of IPP media-col")
+	rootCmd.PersistentFlags().IntVar(&retriesFlag, "retries", 3,
+		"Retries when the printer is busy or not reachable yet, e.g. waking from sleep (0 disables)")
 
 	// Local flags (only for print command)
 	rootCmd.Flags().StringVarP(&profileFlag, "profile", "p", "",
 		"Profile name or ID (alternative to positional)")
-	rootCmd.Flags().StringVar(&pagesFlag, "pages", "",
-		"Page range: '1', '1-5', '2:' (from 2), ':5' (to 5)")
-	rootCmd.Flags().IntVarP(&qualityFlag, "quality", "q", 0,
-		"Quality: 3 (draft), 4 (normal), 5 (best)")
-	rootCmd.Flags().StringVar(&paperFlag, "paper", "",
-		"Paper size override")
-	rootCmd.Flags().StringVar(&trayFlag, "tray", "",
-		"Tray override: Photo, Main, Rear, Auto")
-	rootCmd.Flags().StringVar(&mediaFlag, "media", "",
-		"Media type override")
+	addSettingFlags(rootCmd)
+	rootCmd.Flags().BoolVar(&dryRunFlag, "dry-run", false,
+		"Show the IPP request with every attribute instead of sending it")
+	rootCmd.Flags().StringVar(&dumpIPPFlag, "dump-ipp", "",
+		"Write the raw encoded IPP request (header and document) to a file")
+	rootCmd.Flags().BoolVar(&waitFlag, "wait", false,
+		"Wait until the job finishes; exit non-zero if it is canceled or aborted")
+	rootCmd.Flags().DurationVar(&intervalFlag, "interval", printer.DefaultPollInterval,
+		"Polling interval for --wait")
 }
 
-func runPrint(_ *cobra.Command, args []string) {
-	// Check printer URI
-	if printerURI == "" {
-		log.Fatal("Error: PRINTER_URI environment variable not set\n\n" +
-			"Please set the printer URI:\n" +
-			"  export PRINTER_URI=\"http://localhost:631/printers/EPSON_ET-8550_Series\"\n" +
-			"Or use --printer flag")
-	}
+func runPrint(cmd *cobra.Command, args []string) {
+	requirePrinterURI()
 
 	// Get file and profile from arguments
-	pdfFile := args[0]
+	file := args[0]
 	profile := profileFlag
 
 	// If no --profile flag, check for positional profile argument
@@ -95,9 +133,9 @@ func runPrint(_ *cobra.Command, args []string) {
 		profile = args[1]
 	}
 
-	// Default profile if none specified
+	// Default profile of the printer, or the default profile
 	if profile == "" {
-		profile = "default"
+		profile = cmp.Or(target.Profile, "default")
 	}
 
 	// Get print options
@@ -107,30 +145,35 @@ func runPrint(_ *cobra.Command, args []string) {
 	}
 
 	// Apply overrides
-	if pagesFlag != "" {
-		opts.PageRange = pagesFlag
-	}
-	if qualityFlag > 0 {
-		if qualityFlag < 3 || qualityFlag > 5 {
-			log.Fatal("Error: Quality must be 3 (draft), 4 (normal), or 5 (best)")
+	opts = opts.Overlay(flagOverlay())
+
+	// Check settings against the printer before sending anything. A dry
+	// run only checks what can be checked offline.
+	client := newClient()
+	if dryRunFlag {
+		client.DryRun = true
+		if err := opts.Validate(nil); err != nil {
+			log.Fatalf("Error: invalid print settings:\n%v\n", err)
 		}
-		opts.Quality = qualityFlag
-	}
-	if paperFlag != "" {
-		opts.PaperSize = paperFlag
+	} else {
+		validateOptions(cmd, client, opts)
 	}
-	if trayFlag != "" {
-		opts.Tray = trayFlag
-	}
-	if mediaFlag != "" {
-		opts.MediaType = mediaFlag
+	if dumpIPPFlag != "" {
+		dump, err := os.Create(dumpIPPFlag)
+		if err != nil {
+			log.Fatalf("Error: %v\n", err)
+		}
+		defer func() {
+			_ = dump.Close()
+		}()
+		client.DumpIPP = dump
 	}
 
 	// Print info
 	fmt.Println("=========================================")
-	fmt.Println("PDF PRINT")
+	fmt.Println("PRINT")
 	fmt.Println("=========================================")
-	fmt.Printf("File:        %s\n", pdfFile)
+	fmt.Printf("File:        %s\n", file)
 	fmt.Printf("Profile:     %s\n", profile)
 	fmt.Printf("Paper size:  %s\n", opts.PaperSize)
 	fmt.Printf("Tray:        %s\n", opts.Tray)
@@ -140,25 +183,172 @@ func runPrint(_ *cobra.Command, args []string) {
 	fmt.Println("=========================================")
 	fmt.Println()
 
-	// Print the PDF
-	jobID, err := printer.PrintPDF(printerURI, pdfFile, opts)
+	// Print the document (PDF or image)
+	result, err := client.PrintDocument(cmd.Context(), file, opts)
 	if err != nil {
 		log.Fatalf("Print failed: %v\n", err)
 	}
 
-	fmt.Printf("✓ Print job sent successfully! (Job ID: %d)\n", jobID)
+	if dumpIPPFlag != "" {
+		fmt.Printf("Raw IPP request written to %s\n", dumpIPPFlag)
+	}
+	if dryRunFlag {
+		fmt.Println("\nDry run: nothing was sent to the printer")
+		return
+	}
+
+	fmt.Printf("✓ Print job sent successfully! (Job ID: %d)\n", result.JobID)
+	if warning := result.Warning(); warning != "" {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
+	}
+
+	if waitFlag {
+		job := waitForJob(cmd, client, result.JobID)
+		fmt.Printf("Job %d %s (%d impressions)\n", job.ID, job.State, job.ImpressionsCompleted)
+		if job.State == printer.JobCanceled || job.State == printer.JobAborted {
+			os.Exit(1)
+		}
+	}
+}
+
+// addSettingFlags adds the flags that override individual print settings.
+// They are shared by the print command and 'print profile add|edit'.
+func addSettingFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&pagesFlag, "pages", "",
+		"Page range: '1', '1-5', '2:' (from 2), ':5' (to 5), or a list like '1-3,7,10:'")
+	cmd.Flags().IntVarP(&qualityFlag, "quality", "q", 0,
+		"Quality: 3 (draft), 4 (normal), 5 (best)")
+	cmd.Flags().StringVar(&paperFlag, "paper", "",
+		"Paper size override, e.g. A4.Borderless or a PWG name like iso_a4_210x297mm")
+	cmd.Flags().StringVar(&trayFlag, "tray", "",
+		"Tray override: Photo, Main, Rear, Auto")
+	cmd.Flags().StringVar(&mediaFlag, "media", "",
+		"Media type override")
+	cmd.Flags().IntVar(&copiesFlag, "copies", 0,
+		"Number of copies")
+}
+
+// flagOverlay returns the settings given on the command line as partial
+// print options, to be applied with PrintOptions.Overlay. It exits on
+// invalid quality, copies or page range values.
+func flagOverlay() printer.PrintOptions {
+	if qualityFlag != 0 && (qualityFlag < 3 || qualityFlag > 5) {
+		log.Fatal("Error: Quality must be 3 (draft), 4 (normal), or 5 (best)")
+	}
+	if copiesFlag < 0 {
+		log.Fatal("Error: --copies must be at least 1")
+	}
+	if _, err := printer.ParsePageRanges(pagesFlag); err != nil {
+		log.Fatalf("Error: --pages: %v\n", err)
+	}
+
+	return printer.PrintOptions{
+		PaperSize: paperFlag,
+		Tray:      trayFlag,
+		MediaType: mediaFlag,
+		Quality:   qualityFlag,
+		PageRange: pagesFlag,
+		Copies:    copiesFlag,
+	}
 }
 
 func getOptionsFromProfile(profile string) (printer.PrintOptions, error) {
-	// Try to parse as numeric ID first
+	name, err := resolveProfile(profile)
+	if err != nil {
+		return printer.PrintOptions{}, err
+	}
+	return printer.GetPrintOptions(name)
+}
+
+// resolveProfile returns the profile name for a numeric profile ID or name
+func resolveProfile(profile string) (printer.PrintProfile, error) {
 	if id, err := strconv.Atoi(profile); err == nil {
-		profileName, err := printer.GetProfileByID(id)
+		return printer.GetProfileByID(id)
+	}
+	return printer.PrintProfile(profile), nil
+}
+
+// validateOptions exits with a descriptive error when the printer does not
+// support the paper size, tray, media type or quality in opts
+func validateOptions(cmd *cobra.Command, client *printer.Client, opts printer.PrintOptions) {
+	caps, err := client.Capabilities(cmd.Context())
+	if err != nil {
+		log.Fatalf("Error: %v\n", err)
+	}
+	if err := opts.Validate(caps); err != nil {
+		log.Fatalf("Error: invalid print settings:\n%v\n", err)
+	}
+}
+
+// newClient returns a client for the selected printer, configured from the
+// persistent flags. TLS flags override the TLS settings of a registry entry.
+func newClient() *printer.Client {
+	client, err := printer.NewClientFor(target)
+	if err != nil {
+		log.Fatalf("Error: %v\n", err)
+	}
+	client.Timeout = timeoutFlag
+	client.LegacyAttributes = legacyCUPS
+	client.Retry = printer.RetryPolicy{
+		MaxRetries: retriesFlag,
+		OnRetry: func(attempt int, delay time.Duration, err error) {
+			fmt.Fprintf(os.Stderr, "Printer not ready (%v), retrying in %s (%d/%d)\n",
+				err, delay.Round(100*time.Millisecond), attempt, retriesFlag)
+		},
+	}
+
+	if tlsOpts != (printer.TLSOptions{}) {
+		httpClient, err := printer.NewHTTPClient(tlsOpts)
 		if err != nil {
-			return printer.PrintOptions{}, err
+			log.Fatalf("Error: %v\n", err)
 		}
-		return printer.GetPrintOptions(profileName)
+		client.HTTPClient = httpClient
 	}
 
-	// Otherwise treat as profile name
-	return printer.GetPrintOptions(printer.PrintProfile(profile))
+	return client
+}
+
+// requirePrinterURI selects the printer to use with selectPrinter. It exits
+// with a helpful message when no printer is configured.
+func requirePrinterURI() {
+	var err error
+	if target, err = selectPrinter(); err != nil {
+		log.Fatalf("Error: %v\n", err)
+	}
+	printerURI = target.URI
+
+	if printerURI == "" {
+		log.Fatal("Error: PRINTER_URI environment variable not set\n\n" +
+			"Please set the printer URI:\n" +
+			"  export PRINTER_URI=\"http://localhost:631/printers/EPSON_ET-8550_Series\"\n" +
+			"  export PRINTER_URI=\"ipp://your-printer.local:631/ipp/print\"\n" +
+			"Or use --printer flag, add one with 'print printers add',\n" +
+			"or find and save one with 'print discover --save'")
+	}
+}
+
+// selectPrinter returns the printer to use: the registry entry named by
+// --to, then --printer or PRINTER_URI, then the default printer of the
+// registry, then the URI saved by 'print discover --save'. The URI is empty
+// when no printer is configured.
+func selectPrinter() (printer.PrinterEntry, error) {
+	if toFlag == "" && printerURI != "" {
+		return printer.PrinterEntry{URI: printerURI}, nil
+	}
+
+	cfg, err := openConfig()
+	if err != nil {
+		return printer.PrinterEntry{}, err
+	}
+	if toFlag != "" {
+		entry, err := cfg.Printer(toFlag)
+		if err != nil {
+			return printer.PrinterEntry{}, fmt.Errorf("--to: %w (see 'print printers list')", err)
+		}
+		return entry, nil
+	}
+	if entry, ok := cfg.DefaultPrinterEntry(); ok {
+		return entry, nil
+	}
+	return printer.PrinterEntry{URI: cfg.PrinterURI}, nil
 }
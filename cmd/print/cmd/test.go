@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"log"
 
-	"github.com/Eric-Eklund/epson-printing/pkg/printer"
 	"github.com/spf13/cobra"
 )
 
@@ -15,7 +14,8 @@ var testCmd = &cobra.Command{
 	Use:   "test",
 	Short: "Test IPP connection and display printer status",
 	Long: `Test the IPP connection to the printer and display current status
-including printer state, ink levels for all 6 tanks, and any messages.
+including printer state, ink levels for all tanks, the maintenance box
+(waste ink) and any messages.
 
 Use --json flag to output in JSON format instead of formatted text.`,
 	Example: `  # Test connection and show status
@@ -31,23 +31,18 @@ func init() {
 	testCmd.Flags().BoolVar(&jsonOutput, "json", false, "Output in JSON format")
 }
 
-func runTest(_ *cobra.Command, _ []string) {
-	if printerURI == "" {
-		log.Fatal("Error: PRINTER_URI environment variable not set\n\n" +
-			"Please set the printer URI:\n" +
-			"  export PRINTER_URI=\"http://localhost:631/printers/EPSON_ET-8550_Series\"\n" +
-			"Or use --printer flag")
-	}
+func runTest(cmd *cobra.Command, _ []string) {
+	requirePrinterURI()
 
 	if !jsonOutput {
 		fmt.Println("=============================================")
-		fmt.Println("Epson ET-8550 IPP Connection Test")
+		fmt.Println("Printer IPP Connection Test")
 		fmt.Println("=============================================")
 		fmt.Printf("Testing connection to: %s\n\n", printerURI)
 	}
 
 	// Get printer information
-	info, err := printer.GetPrinterInfo(printerURI)
+	info, err := newClient().Info(cmd.Context())
 	if err != nil {
 		log.Fatalf("Error: Failed to connect to printer\n%v\n", err)
 	}
@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Eric-Eklund/epson-printing/pkg/printer"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Watch command flags
+	watchRules    []string
+	watchInterval time.Duration
+	watchRepeat   time.Duration
+	emailTo       []string
+	smtpServer    string
+	smtpFrom      string
+	webhookURLs   []string
+	execCommand   string
+)
+
+// watchCmd represents the watch command
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Watch the printer and send alerts for low ink and errors",
+	Long: `Poll the printer status and send an alert when a rule starts to match,
+and again when it is resolved. Alerts are printed and optionally sent by
+email, to webhooks (JSON POST) or to a command.
+
+Rules (--rule, repeatable):
+  ink<15               Any ink tank below 15%
+  ink:Cyan<10          The Cyan tank below 10%
+  state:stopped>5m     Printer stopped for more than 5 minutes
+  reason:media-empty   printer-state-reasons contains media-empty (-error, -warning)
+  unreachable>10m      Printer not answering for more than 10 minutes
+
+Each alert is sent once while its condition holds (see --repeat). Ink
+alerts are resolved when the level rises 5% above the threshold, so a level
+hovering at the threshold does not alert on every poll. An alert that
+email, a webhook or the command failed to deliver is sent to it again on
+the next poll.
+
+Email is sent through --smtp with the SMTP_USER and SMTP_PASSWORD
+environment variables; --exec runs a shell command with the alert as JSON
+on stdin and in ALERT_STATUS, ALERT_PRINTER, ALERT_RULE and ALERT_MESSAGE.`,
+	Example: `  # Default rules, alerts on stdout
+  print watch
+
+  # Email when a tank runs low or the printer is stopped for 5 minutes
+  SMTP_USER=me@example.com SMTP_PASSWORD=... print watch \
+    --rule 'ink<15' --rule 'state:stopped>5m' \
+    --smtp smtp.example.com:587 --email me@example.com
+
+  # Post to a webhook and show a desktop notification
+  print watch --webhook https://hooks.example.com/printer \
+    --exec 'notify-send "$ALERT_PRINTER" "$ALERT_MESSAGE"'`,
+	Args: cobra.NoArgs,
+	Run:  runWatch,
+}
+
+func init() {
+	rootCmd.AddCommand(watchCmd)
+	watchCmd.Flags().StringArrayVar(&watchRules, "rule", printer.DefaultWatchRules,
+		"Alert rule (repeatable), e.g. ink<15, state:stopped>5m, reason:media-empty, unreachable>10m")
+	watchCmd.Flags().DurationVar(&watchInterval, "interval", time.Minute,
+		"Polling interval")
+	watchCmd.Flags().DurationVar(&watchRepeat, "repeat", 0,
+		"Send alerts again while they are still active after this long, e.g. 24h (0 sends once)")
+	watchCmd.Flags().StringSliceVar(&emailTo, "email", nil,
+		"Email alerts to these addresses (requires --smtp)")
+	watchCmd.Flags().StringVar(&smtpServer, "smtp", os.Getenv("SMTP_SERVER"),
+		"SMTP server host:port for --email (default from SMTP_SERVER env var)")
+	watchCmd.Flags().StringVar(&smtpFrom, "from", os.Getenv("SMTP_FROM"),
+		"Sender address for --email (default from SMTP_FROM, or SMTP_USER)")
+	watchCmd.Flags().StringArrayVar(&webhookURLs, "webhook", nil,
+		"POST alerts as JSON to this URL (repeatable)")
+	watchCmd.Flags().StringVar(&execCommand, "exec", "",
+		"Run this shell command for each alert")
+}
+
+func runWatch(cmd *cobra.Command, _ []string) {
+	requirePrinterURI()
+
+	rules, err := printer.ParseRules(watchRules)
+	if err != nil {
+		log.Fatalf("Error: %v\n", err)
+	}
+
+	notifiers, targets := watchNotifiers()
+	watcher := printer.NewWatcher(targetName(), rules, notifiers...)
+	watcher.Repeat = watchRepeat
+	watcher.OnError = func(err error) {
+		log.Printf("Error: %v\n", err)
+	}
+
+	ruleNames := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		ruleNames = append(ruleNames, rule.String())
+	}
+	fmt.Printf("Watching %s every %s\n", targetName(), watchInterval)
+	fmt.Printf("Rules:  %s\n", strings.Join(ruleNames, ", "))
+	fmt.Printf("Alerts: %s\n\n", strings.Join(targets, ", "))
+
+	err = watcher.Run(cmd.Context(), newClient(), watchInterval)
+	if err != nil && !errors.Is(err, context.Canceled) {
+		log.Fatalf("Error: %v\n", err)
+	}
+}
+
+// watchNotifiers builds the notifiers selected by the flags, and describes
+// where alerts go. Alerts are always printed.
+func watchNotifiers() ([]printer.Notifier, []string) {
+	notifiers := []printer.Notifier{&printer.WriterNotifier{W: os.Stdout}}
+	targets := []string{"stdout"}
+
+	if len(emailTo) > 0 {
+		if smtpServer == "" {
+			log.Fatalf("Error: --email requires --smtp or SMTP_SERVER\n")
+		}
+		user := os.Getenv("SMTP_USER")
+		notifiers = append(notifiers, &printer.SMTPNotifier{
+			Addr:     smtpServer,
+			Username: user,
+			Password: os.Getenv("SMTP_PASSWORD"),
+			From:     cmp.Or(smtpFrom, user, "epson-printing@localhost"),
+			To:       emailTo,
+		})
+		targets = append(targets, "email "+strings.Join(emailTo, ", "))
+	}
+	for _, url := range webhookURLs {
+		notifiers = append(notifiers, &printer.WebhookNotifier{URL: url})
+		targets = append(targets, "webhook "+url)
+	}
+	if execCommand != "" {
+		notifiers = append(notifiers, &printer.ExecNotifier{Command: execCommand})
+		targets = append(targets, "exec")
+	}
+	return notifiers, targets
+}
@@ -11,7 +11,8 @@ import (
 func main() {
 	fmt.Println("===========================================")
 	fmt.Println("Epson ET-8550 IPP Connection Test")
-	fmt.Println("===========================================\n")
+	fmt.Println("===========================================")
+	fmt.Println()
 
 	// Get printer URI from environment variable
 	printerURI := os.Getenv("PRINTER_URI")
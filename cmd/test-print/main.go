@@ -46,10 +46,13 @@ func main() {
 	}
 
 	// Print the PDF
-	jobID, err := printer.PrintPDF(printerURI, testPDF, opts)
+	result, err := printer.PrintPDF(printerURI, testPDF, opts)
 	if err != nil {
 		log.Fatalf("Error: %v\n", err)
 	}
+	if warning := result.Warning(); warning != "" {
+		fmt.Printf("Warning: %s\n", warning)
+	}
 
-	fmt.Printf("✓ Print job sent successfully! (Job ID: %d)\n", jobID)
+	fmt.Printf("✓ Print job sent successfully! (Job ID: %d)\n", result.JobID)
 }
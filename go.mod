@@ -2,11 +2,14 @@ module github.com/Eric-Eklund/epson-printing
 
 go 1.25.5
 
-require github.com/OpenPrinting/goipp v1.2.0
+require (
+	github.com/OpenPrinting/goipp v1.2.0
+	github.com/go-pdf/fpdf v0.9.0
+	github.com/spf13/cobra v1.10.2
+	golang.org/x/net v0.58.0
+)
 
 require (
-	github.com/go-pdf/fpdf v0.9.0 // indirect
 	github.com/inconshreveable/mousetrap v1.1.0 // indirect
-	github.com/spf13/cobra v1.10.2 // indirect
 	github.com/spf13/pflag v1.0.9 // indirect
 )
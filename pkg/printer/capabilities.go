@@ -0,0 +1,116 @@
+package printer
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/OpenPrinting/goipp"
+)
+
+// capabilityAttributes are the printer attributes parsed into Capabilities
+var capabilityAttributes = []string{
+	"printer-make-and-model",
+	"media-supported",
+	"media-source-supported",
+	"media-type-supported",
+	"print-quality-supported",
+	"document-format-supported",
+}
+
+// Capabilities lists the values a printer accepts for job settings.
+// Empty lists mean the printer did not report the attribute.
+type Capabilities struct {
+	MakeAndModel    string   `json:"make_and_model,omitempty"`
+	Media           []string `json:"media,omitempty"`            // PWG media names, e.g. "iso_a4_210x297mm"
+	MediaSources    []string `json:"media_sources,omitempty"`    // Trays, e.g. "main", "rear"
+	MediaTypes      []string `json:"media_types,omitempty"`      // e.g. "stationery", "photographic-glossy"
+	PrintQualities  []int    `json:"print_qualities,omitempty"`  // 3=draft, 4=normal, 5=high
+	DocumentFormats []string `json:"document_formats,omitempty"` // MIME types
+}
+
+// Capabilities retrieves the supported job settings via Get-Printer-Attributes
+func (c *Client) Capabilities(ctx context.Context) (*Capabilities, error) {
+	msg, err := c.Attributes(ctx, capabilityAttributes...)
+	if err != nil {
+		return nil, fmt.Errorf("querying printer capabilities: %w", err)
+	}
+
+	return parseCapabilities(msg), nil
+}
+
+// Model returns the model descriptor for the printer's make and model
+func (caps *Capabilities) Model() Model {
+	return ModelFor(caps.MakeAndModel)
+}
+
+// SupportsFormat reports whether the printer accepts documents of the given
+// MIME type natively
+func (caps *Capabilities) SupportsFormat(format string) bool {
+	return slices.Contains(caps.DocumentFormats, format)
+}
+
+// parseCapabilities builds Capabilities from a printer attributes response
+func parseCapabilities(msg *goipp.Message) *Capabilities {
+	caps := &Capabilities{
+		MakeAndModel:    getString(msg.Printer, "printer-make-and-model"),
+		Media:           getStringValues(msg, "media-supported"),
+		MediaSources:    getStringValues(msg, "media-source-supported"),
+		MediaTypes:      getStringValues(msg, "media-type-supported"),
+		DocumentFormats: getStringValues(msg, "document-format-supported"),
+	}
+
+	if attr := getAttribute(msg, "print-quality-supported"); attr != nil {
+		for _, v := range attr.Values {
+			if quality, ok := v.V.(goipp.Integer); ok {
+				caps.PrintQualities = append(caps.PrintQualities, int(quality))
+			}
+		}
+	}
+
+	return caps
+}
+
+// containsFold reports whether values contains s, ignoring case
+func containsFold(values []string, s string) bool {
+	return slices.ContainsFunc(values, func(v string) bool {
+		return strings.EqualFold(v, s)
+	})
+}
+
+// suggest returns the candidate closest to value, or "" when none is close
+// enough to be a likely typo
+func suggest(value string, candidates []string) string {
+	value = strings.ToLower(value)
+	best, bestDistance := "", len(value)/3+2
+	for _, candidate := range candidates {
+		if d := levenshtein(value, strings.ToLower(candidate)); d < bestDistance {
+			best, bestDistance = candidate, d
+		}
+	}
+	return best
+}
+
+// levenshtein returns the edit distance between two strings
+func levenshtein(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(b)]
+}
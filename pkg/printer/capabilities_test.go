@@ -0,0 +1,192 @@
+package printer
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/OpenPrinting/goipp"
+)
+
+// Test helper: capabilities of an ET-8550 style printer
+func createMockCapabilities() *Capabilities {
+	return &Capabilities{
+		MakeAndModel:    "EPSON ET-8550 Series",
+		Media:           []string{"na_index-4x6_4x6in", "iso_a4_210x297mm", "iso_a3_297x420mm"},
+		MediaSources:    []string{"auto", "main", "rear", "photo"},
+		MediaTypes:      []string{"stationery", "photographic-glossy", "photographic-matte"},
+		PrintQualities:  []int{3, 4, 5},
+		DocumentFormats: []string{FormatPDF, FormatJPEG},
+	}
+}
+
+func TestClient_Capabilities(t *testing.T) {
+	server := newTestIPPServer(t, func(_ *http.Request, req *goipp.Message) *goipp.Message {
+		resp := goipp.NewResponse(goipp.DefaultVersion, goipp.StatusOk, req.RequestID)
+		resp.Printer.Add(goipp.MakeAttr("printer-make-and-model", goipp.TagText, goipp.String("EPSON ET-8550 Series")))
+		resp.Printer.Add(goipp.MakeAttr("media-supported", goipp.TagKeyword,
+			goipp.String("iso_a4_210x297mm"), goipp.String("na_index-4x6_4x6in")))
+		resp.Printer.Add(goipp.MakeAttr("media-source-supported", goipp.TagKeyword,
+			goipp.String("main"), goipp.String("rear")))
+		resp.Printer.Add(goipp.MakeAttr("media-type-supported", goipp.TagKeyword,
+			goipp.String("stationery")))
+		resp.Printer.Add(goipp.MakeAttr("print-quality-supported", goipp.TagEnum,
+			goipp.Integer(3), goipp.Integer(4), goipp.Integer(5)))
+		resp.Printer.Add(goipp.MakeAttr("document-format-supported", goipp.TagMimeType,
+			goipp.String(FormatPDF)))
+		return resp
+	})
+
+	caps, err := NewClient(server.URL).Capabilities(context.Background())
+	if err != nil {
+		t.Fatalf("Capabilities() error = %v", err)
+	}
+
+	if caps.MakeAndModel != "EPSON ET-8550 Series" {
+		t.Errorf("MakeAndModel = %q", caps.MakeAndModel)
+	}
+	if !slices.Equal(caps.Media, []string{"iso_a4_210x297mm", "na_index-4x6_4x6in"}) {
+		t.Errorf("Media = %v", caps.Media)
+	}
+	if !slices.Equal(caps.MediaSources, []string{"main", "rear"}) {
+		t.Errorf("MediaSources = %v", caps.MediaSources)
+	}
+	if !slices.Equal(caps.MediaTypes, []string{"stationery"}) {
+		t.Errorf("MediaTypes = %v", caps.MediaTypes)
+	}
+	if !slices.Equal(caps.PrintQualities, []int{3, 4, 5}) {
+		t.Errorf("PrintQualities = %v", caps.PrintQualities)
+	}
+	if !caps.SupportsFormat(FormatPDF) || caps.SupportsFormat(FormatPNG) {
+		t.Errorf("DocumentFormats = %v", caps.DocumentFormats)
+	}
+}
+
+func TestPrintOptions_Validate(t *testing.T) {
+	caps := createMockCapabilities()
+
+	tests := []struct {
+		name    string
+		opts    PrintOptions
+		wantErr string
+	}{
+		{
+			name: "valid profile settings",
+			opts: PrintOptions{PaperSize: "4x6.Borderless", Tray: "Photo", MediaType: "photographic-glossy", Quality: 5},
+		},
+		{
+			name: "PWG media name",
+			opts: PrintOptions{PaperSize: "iso_a3_297x420mm"},
+		},
+		{
+			name: "unset options",
+			opts: PrintOptions{},
+		},
+		{
+			name:    "paper typo",
+			opts:    PrintOptions{PaperSize: "A3.Borderles"},
+			wantErr: `unsupported paper size "A3.Borderles" (did you mean "A3.Borderless"?)`,
+		},
+		{
+			name:    "paper not loaded in printer",
+			opts:    PrintOptions{PaperSize: "13x19.Borderless"},
+			wantErr: `unsupported paper size "13x19.Borderless": printer does not list na_super-b_13x19in (supported: 4x6, 4x6.Borderless, A3, A3.Borderless, A4, A4.Borderless, na_index-4x6_4x6in, iso_a4_210x297mm, iso_a3_297x420mm)`,
+		},
+		{
+			name:    "tray typo",
+			opts:    PrintOptions{Tray: "Reer"},
+			wantErr: `unsupported tray "Reer" (did you mean "rear"?)`,
+		},
+		{
+			name:    "unknown media type",
+			opts:    PrintOptions{MediaType: "canvas"},
+			wantErr: `unsupported media type "canvas" (supported: stationery, photographic-glossy, photographic-matte)`,
+		},
+		{
+			name:    "unsupported quality",
+			opts:    PrintOptions{Quality: 7},
+			wantErr: "unsupported quality 7 (supported: 3 4 5)",
+		},
+		{
+			name:    "malformed page range",
+			opts:    PrintOptions{PageRange: "1,,3"},
+			wantErr: `invalid page range "1,,3": empty page range`,
+		},
+		{
+			name:    "multiple errors",
+			opts:    PrintOptions{Tray: "Reer", Quality: 7},
+			wantErr: "unsupported tray \"Reer\" (did you mean \"rear\"?)\nunsupported quality 7 (supported: 3 4 5)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.opts.Validate(caps)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("Validate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPrintOptions_Validate_EmptyCapabilities(t *testing.T) {
+	opts := PrintOptions{PaperSize: "A4", Tray: "Anything", MediaType: "anything", Quality: 9}
+
+	if err := opts.Validate(&Capabilities{}); err != nil {
+		t.Errorf("Validate() error = %v, want nil when the printer reports nothing", err)
+	}
+	if err := opts.Validate(nil); err != nil {
+		t.Errorf("Validate(nil) error = %v, want nil", err)
+	}
+
+	// Unknown paper names are still caught
+	opts.PaperSize = "A4.Borderles"
+	if err := opts.Validate(nil); err == nil {
+		t.Error("Validate() expected error for unknown paper size")
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	candidates := []string{"main", "rear", "photo", "auto"}
+
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"Reer", "rear"},
+		{"MAIN", "main"},
+		{"fhoto", "photo"},
+		{"manual-feed", ""},
+	}
+
+	for _, tt := range tests {
+		if got := suggest(tt.value, candidates); got != tt.want {
+			t.Errorf("suggest(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"kitten", "sitting", 3},
+		{"a3.borderles", "a3.borderless", 1},
+	}
+
+	for _, tt := range tests {
+		if got := levenshtein(tt.a, tt.b); got != tt.want {
+			t.Errorf("levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,280 @@
+package printer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/OpenPrinting/goipp"
+)
+
+// DefaultTimeout is the per-request timeout used by clients created with
+// NewClient. It bounds connecting and waiting for the printer; a document
+// upload may take longer as long as data keeps flowing.
+const DefaultTimeout = 2 * time.Minute
+
+// Client is a reusable IPP client for a single printer.
+// A Client is safe for concurrent use; share one per printer to reuse
+// the connection pool of its HTTP client.
+type Client struct {
+	URI        string        // Printer URI: ipp://, ipps://, http:// or https://
+	HTTPClient *http.Client  // HTTP client used for requests (default: http.DefaultClient)
+	UserName   string        // requesting-user-name sent with every request
+	Password   string        // HTTP basic auth password for UserName, sent when set
+	Language   string        // attributes-natural-language sent with every request
+	Timeout    time.Duration // Per-request timeout without progress, applied when > 0
+
+	// LegacyAttributes sends CUPS PPD job attributes (PageSize, InputSlot)
+	// instead of IPP media-col, for older CUPS queues
+	LegacyAttributes bool
+
+	// DryRun makes Print describe the Print-Job request on DryRunOutput
+	// (default os.Stdout) instead of sending it to the printer
+	DryRun       bool
+	DryRunOutput io.Writer
+
+	// DumpIPP receives the raw encoded request of every print job, the IPP
+	// header followed by the document, for offline inspection. A retried
+	// job is dumped once.
+	DumpIPP io.Writer
+
+	// Retry repeats requests that failed transiently; the zero value
+	// disables retrying
+	Retry RetryPolicy
+
+	requestID atomic.Uint32
+}
+
+// NewClient returns a Client for the given printer URI with default settings
+func NewClient(printerURI string) *Client {
+	return &Client{
+		URI:      printerURI,
+		UserName: os.Getenv("USER"),
+		Language: "en-US",
+		Timeout:  DefaultTimeout,
+	}
+}
+
+// Attributes retrieves printer attributes via Get-Printer-Attributes.
+// If no names are given, all attributes are requested.
+func (c *Client) Attributes(ctx context.Context, names ...string) (*goipp.Message, error) {
+	if len(names) == 0 {
+		names = []string{"all"}
+	}
+
+	msg := c.newRequest(goipp.OpGetPrinterAttributes)
+	msg.Operation.Add(requestedAttributes(names...))
+
+	return c.send(ctx, msg, nil)
+}
+
+// newRequest builds an IPP request with the standard operation attributes
+func (c *Client) newRequest(op goipp.Op) *goipp.Message {
+	msg := goipp.NewRequest(goipp.DefaultVersion, op, c.requestID.Add(1))
+
+	msg.Operation.Add(goipp.MakeAttr("attributes-charset",
+		goipp.TagCharset, goipp.String("utf-8")))
+	msg.Operation.Add(goipp.MakeAttr("attributes-natural-language",
+		goipp.TagLanguage, goipp.String(c.Language)))
+	msg.Operation.Add(goipp.MakeAttr("printer-uri",
+		goipp.TagURI, goipp.String(c.URI)))
+	if c.UserName != "" {
+		msg.Operation.Add(goipp.MakeAttr("requesting-user-name",
+			goipp.TagName, goipp.String(c.UserName)))
+	}
+
+	return msg
+}
+
+// send posts an IPP request, optionally followed by a document body, and
+// decodes the response. Transient failures are retried according to Retry.
+// HTTP and IPP error statuses are returned as *IPPError; the response is
+// returned even when the printer reports an IPP error status, so callers
+// can inspect it.
+func (c *Client) send(ctx context.Context, msg *goipp.Message, document io.Reader) (*goipp.Message, error) {
+	// Encode request header
+	header, err := msg.EncodeBytes()
+	if err != nil {
+		return nil, fmt.Errorf("encoding IPP request: %w", err)
+	}
+
+	// ipp:// and ipps:// URIs are reached over plain HTTP(S); the original
+	// URI is still sent as the printer-uri attribute
+	target, err := transportURL(c.URI)
+	if err != nil {
+		return nil, err
+	}
+
+	// Dump the request once, so a retried attempt does not repeat the header
+	if document != nil && c.DumpIPP != nil {
+		if _, err := c.DumpIPP.Write(header); err != nil {
+			return nil, fmt.Errorf("writing IPP dump: %w", err)
+		}
+		document = io.TeeReader(document, c.DumpIPP)
+	}
+
+	// Count document bytes to know whether a failed job may be resent
+	var counter *countingReader
+	if document != nil {
+		counter = &countingReader{r: document}
+		document = counter
+	}
+
+	op := goipp.Op(msg.Code)
+	for retry := 1; ; retry++ {
+		respMsg, err := c.roundTrip(ctx, op, target, header, document)
+		if err == nil || retry > c.Retry.MaxRetries {
+			return respMsg, err
+		}
+
+		var sent int64
+		if counter != nil {
+			sent = counter.n
+		}
+		if !retryable(op, err, sent) {
+			return respMsg, err
+		}
+
+		delay := c.Retry.delay(retry)
+		if c.Retry.OnRetry != nil {
+			c.Retry.OnRetry(retry, delay, err)
+		}
+		if wait(ctx, delay) != nil {
+			return respMsg, err
+		}
+	}
+}
+
+// roundTrip performs a single HTTP exchange of an encoded request, applying
+// the per-request timeout. The timeout bounds connecting and waiting for the
+// response, not the whole upload: it restarts whenever document data is
+// sent, so a large document on a slow network is only cancelled when the
+// upload stalls.
+func (c *Client) roundTrip(ctx context.Context, op goipp.Op, target string, header []byte, document io.Reader) (*goipp.Message, error) {
+	// Stream header followed by document body, if any
+	var body io.Reader = bytes.NewReader(header)
+	if document != nil {
+		body = io.MultiReader(body, document)
+	}
+
+	if c.Timeout > 0 {
+		idle := newIdleTimeout(ctx, c.Timeout)
+		defer idle.stop()
+		ctx = idle.ctx
+		body = &progressReader{r: body, progress: idle.reset}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, body)
+	if err != nil {
+		return nil, fmt.Errorf("creating HTTP request: %w", err)
+	}
+	req.Header.Set("Content-Type", goipp.ContentType)
+	if c.Password != "" {
+		req.SetBasicAuth(c.UserName, c.Password)
+	}
+	if document != nil {
+		req.ContentLength = -1 // Unknown length: use chunked transfer encoding
+	}
+
+	// Send HTTP request
+	resp, err := c.httpClient().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("sending HTTP request: %w", timeoutCause(ctx, err))
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	// CUPS answers authentication and unknown queue errors with an HTML
+	// page instead of an IPP response
+	if resp.StatusCode != http.StatusOK {
+		return nil, &IPPError{Op: op, HTTPStatus: resp.StatusCode}
+	}
+
+	// Decode response
+	var respMsg goipp.Message
+	err = respMsg.Decode(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("decoding response: %w", timeoutCause(ctx, err))
+	}
+
+	// Check for errors
+	status := goipp.Status(respMsg.Code)
+	if status != goipp.StatusOk && status != goipp.StatusOkIgnoredOrSubstituted {
+		return &respMsg, &IPPError{
+			Op:         op,
+			Status:     status,
+			Message:    getString(respMsg.Operation, "status-message"),
+			HTTPStatus: resp.StatusCode,
+		}
+	}
+
+	return &respMsg, nil
+}
+
+// httpClient returns the configured HTTP client or http.DefaultClient
+func (c *Client) httpClient() *http.Client {
+	if c.HTTPClient != nil {
+		return c.HTTPClient
+	}
+	return http.DefaultClient
+}
+
+// idleTimeout cancels a context when no progress is reported for a while
+type idleTimeout struct {
+	ctx     context.Context
+	timeout time.Duration
+	timer   *time.Timer
+	cancel  context.CancelCauseFunc
+}
+
+// newIdleTimeout returns an idleTimeout whose context is canceled with a
+// context.DeadlineExceeded cause after timeout without progress
+func newIdleTimeout(parent context.Context, timeout time.Duration) *idleTimeout {
+	ctx, cancel := context.WithCancelCause(parent)
+	t := &idleTimeout{ctx: ctx, timeout: timeout, cancel: cancel}
+	t.timer = time.AfterFunc(timeout, func() {
+		cancel(fmt.Errorf("no response from printer within %s: %w", timeout, context.DeadlineExceeded))
+	})
+	return t
+}
+
+// reset restarts the timeout after progress
+func (t *idleTimeout) reset() {
+	t.timer.Reset(t.timeout)
+}
+
+// stop releases the timer and the context
+func (t *idleTimeout) stop() {
+	t.timer.Stop()
+	t.cancel(nil)
+}
+
+// timeoutCause returns the cause of a request error: the timeout when the
+// idle timer canceled ctx, or err itself
+func timeoutCause(ctx context.Context, err error) error {
+	if cause := context.Cause(ctx); errors.Is(cause, context.DeadlineExceeded) {
+		return cause
+	}
+	return err
+}
+
+// progressReader calls progress whenever data is read
+type progressReader struct {
+	r        io.Reader
+	progress func()
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	if n > 0 {
+		p.progress()
+	}
+	return n, err
+}
@@ -0,0 +1,233 @@
+package printer
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/OpenPrinting/goipp"
+)
+
+// Test helper: start an IPP server that decodes each request and replies
+// with the message returned by handle. The request body is positioned at
+// the start of the document data, if any.
+func newTestIPPServer(t testing.TB, handle func(r *http.Request, req *goipp.Message) *goipp.Message) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(ippHandler(handle))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+// Test helper: wrap an IPP request handler as an http.Handler
+func ippHandler(handle func(r *http.Request, req *goipp.Message) *goipp.Message) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req goipp.Message
+		if err := req.Decode(r.Body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		resp := handle(r, &req)
+		w.Header().Set("Content-Type", goipp.ContentType)
+		_ = resp.Encode(w)
+	})
+}
+
+// Test helper: build a successful response carrying the mock printer attributes
+func mockPrinterResponse(req *goipp.Message) *goipp.Message {
+	resp := goipp.NewResponse(goipp.DefaultVersion, goipp.StatusOk, req.RequestID)
+	resp.Printer = createMockMessage().Printer
+	return resp
+}
+
+func TestNewClient(t *testing.T) {
+	t.Setenv("USER", "tester")
+
+	c := NewClient("http://localhost:631/printers/Test")
+
+	if c.URI != "http://localhost:631/printers/Test" {
+		t.Errorf("unexpected URI %s", c.URI)
+	}
+	if c.UserName != "tester" {
+		t.Errorf("expected UserName 'tester', got %s", c.UserName)
+	}
+	if c.Language != "en-US" {
+		t.Errorf("expected Language 'en-US', got %s", c.Language)
+	}
+	if c.Timeout != DefaultTimeout {
+		t.Errorf("expected Timeout %v, got %v", DefaultTimeout, c.Timeout)
+	}
+}
+
+func TestClient_RequestAttributes(t *testing.T) {
+	var got *goipp.Message
+	server := newTestIPPServer(t, func(_ *http.Request, req *goipp.Message) *goipp.Message {
+		got = req
+		return mockPrinterResponse(req)
+	})
+
+	c := NewClient(server.URL)
+	c.UserName = "alice"
+	c.Language = "sv-SE"
+
+	if _, err := c.Attributes(context.Background(), "printer-state", "marker-levels"); err != nil {
+		t.Fatalf("Attributes() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{"attributes-natural-language", "sv-SE"},
+		{"printer-uri", server.URL},
+		{"requesting-user-name", "alice"},
+	}
+	for _, tt := range tests {
+		attr := findAttribute(got.Operation, tt.name)
+		if attr == nil {
+			t.Errorf("missing operation attribute %s", tt.name)
+			continue
+		}
+		if attr.Values[0].V.String() != tt.expected {
+			t.Errorf("%s = %s, expected %s", tt.name, attr.Values[0].V, tt.expected)
+		}
+	}
+
+	requested := findAttribute(got.Operation, "requested-attributes")
+	if requested == nil || len(requested.Values) != 2 {
+		t.Fatalf("expected 2 requested-attributes, got %v", requested)
+	}
+}
+
+func TestClient_Info(t *testing.T) {
+	server := newTestIPPServer(t, func(_ *http.Request, req *goipp.Message) *goipp.Message {
+		return mockPrinterResponse(req)
+	})
+
+	info, err := NewClient(server.URL).Info(context.Background())
+	if err != nil {
+		t.Fatalf("Info() error = %v", err)
+	}
+
+	if info.Name != "Test Printer" {
+		t.Errorf("expected name 'Test Printer', got %s", info.Name)
+	}
+	if info.State != "Idle" {
+		t.Errorf("expected state 'Idle', got %s", info.State)
+	}
+	if len(info.InkLevels) != 2 {
+		t.Errorf("expected 2 ink levels, got %d", len(info.InkLevels))
+	}
+}
+
+func TestClient_Timeout(t *testing.T) {
+	release := make(chan struct{})
+	server := newTestIPPServer(t, func(_ *http.Request, req *goipp.Message) *goipp.Message {
+		<-release
+		return mockPrinterResponse(req)
+	})
+	defer close(release)
+
+	c := NewClient(server.URL)
+	c.Timeout = 50 * time.Millisecond
+
+	_, err := c.Attributes(context.Background())
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+}
+
+// slowReader returns chunks of a document with a delay before each
+type slowReader struct {
+	chunks int
+	delay  time.Duration
+}
+
+func (r *slowReader) Read(p []byte) (int, error) {
+	if r.chunks == 0 {
+		return 0, io.EOF
+	}
+	time.Sleep(r.delay)
+	r.chunks--
+	return copy(p, "%PDF-1.7 chunk\n"), nil
+}
+
+func TestClient_Timeout_SlowUpload(t *testing.T) {
+	var received int
+	server := newTestPrintServer(t, func(_ *http.Request, _ *goipp.Message, doc io.Reader) {
+		data, _ := io.ReadAll(doc)
+		received = len(data)
+	})
+
+	c := NewClient(server.URL)
+	c.Timeout = 100 * time.Millisecond
+
+	// The upload takes about 300ms, but data arrives every 30ms
+	start := time.Now()
+	document := &slowReader{chunks: 10, delay: 30 * time.Millisecond}
+	if _, err := c.Print(context.Background(), "large.pdf", FormatPDF, document, DefaultPrintOptions()); err != nil {
+		t.Fatalf("Print() error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < c.Timeout {
+		t.Errorf("upload took %s, want longer than the %s timeout", elapsed, c.Timeout)
+	}
+	if received != 10*len("%PDF-1.7 chunk\n") {
+		t.Errorf("printer received %d bytes", received)
+	}
+
+	// A stalled upload still times out
+	stalled := &slowReader{chunks: 1, delay: 300 * time.Millisecond}
+	_, err := c.Print(context.Background(), "stalled.pdf", FormatPDF, stalled, DefaultPrintOptions())
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("stalled upload: expected deadline exceeded, got %v", err)
+	}
+}
+
+func TestClient_CustomHTTPClient(t *testing.T) {
+	server := newTestIPPServer(t, func(_ *http.Request, req *goipp.Message) *goipp.Message {
+		return mockPrinterResponse(req)
+	})
+
+	requests := 0
+	c := NewClient(server.URL)
+	c.HTTPClient = &http.Client{
+		Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			requests++
+			return http.DefaultTransport.RoundTrip(r)
+		}),
+	}
+
+	if _, err := c.Info(context.Background()); err != nil {
+		t.Fatalf("Info() error = %v", err)
+	}
+	if requests != 1 {
+		t.Errorf("expected 1 request through custom client, got %d", requests)
+	}
+}
+
+func TestClient_ErrorStatus(t *testing.T) {
+	server := newTestIPPServer(t, func(_ *http.Request, req *goipp.Message) *goipp.Message {
+		return goipp.NewResponse(goipp.DefaultVersion, goipp.StatusErrorNotFound, req.RequestID)
+	})
+
+	_, err := NewClient(server.URL).Attributes(context.Background())
+	if err == nil {
+		t.Fatal("expected error for client-error-not-found, got nil")
+	}
+	if !IsNotFound(err) {
+		t.Errorf("IsNotFound(%v) = false, want true", err)
+	}
+}
+
+// roundTripperFunc adapts a function to http.RoundTripper
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
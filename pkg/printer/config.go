@@ -0,0 +1,122 @@
+package printer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// configDirName is the directory below the user config directory that holds
+// this tool's configuration files
+const configDirName = "epson-printing"
+
+// ConfigDir returns the configuration directory: $XDG_CONFIG_HOME/epson-printing
+// when XDG_CONFIG_HOME is set, otherwise epson-printing in the platform's user
+// config directory (e.g. ~/.config on Linux)
+func ConfigDir() (string, error) {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, configDirName), nil
+	}
+
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("finding config directory: %w", err)
+	}
+	return filepath.Join(dir, configDirName), nil
+}
+
+// DataDir returns the data directory for files the tool accumulates, such
+// as the ink history: $XDG_DATA_HOME/epson-printing when XDG_DATA_HOME is
+// set, otherwise ~/.local/share/epson-printing
+func DataDir() (string, error) {
+	if dir := os.Getenv("XDG_DATA_HOME"); dir != "" {
+		return filepath.Join(dir, configDirName), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("finding data directory: %w", err)
+	}
+	return filepath.Join(home, ".local", "share", configDirName), nil
+}
+
+// writeFileAtomic writes data to a temporary file next to path and renames it
+// into place, so a crash never leaves a half-written config file
+func writeFileAtomic(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("creating config directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("creating temporary file: %w", err)
+	}
+	defer func() {
+		_ = os.Remove(tmp.Name())
+	}()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("saving %s: %w", path, err)
+	}
+	return nil
+}
+
+// ConfigFileName is the name of the settings file in ConfigDir
+const ConfigFileName = "config.json"
+
+// Config holds settings saved in config.json
+type Config struct {
+	Path       string `json:"-"`
+	PrinterURI string `json:"printer_uri,omitempty"` // Used when PRINTER_URI and --printer are not set
+
+	Printers       []PrinterEntry `json:"printers,omitempty"`        // Named printers (see registry.go)
+	DefaultPrinter string         `json:"default_printer,omitempty"` // Name of the printer used by default
+}
+
+// DefaultConfigPath returns the path of config.json in ConfigDir
+func DefaultConfigPath() (string, error) {
+	dir, err := ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, ConfigFileName), nil
+}
+
+// LoadConfig reads settings from path. A missing file yields an empty
+// config that is created on Save.
+func LoadConfig(path string) (*Config, error) {
+	cfg := &Config{Path: path}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading config: %w", err)
+	}
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	if err := cfg.validatePrinters(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// Save writes the config to its path, creating the config directory if needed
+func (c *Config) Save() error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding config: %w", err)
+	}
+	return writeFileAtomic(c.Path, append(data, '\n'))
+}
@@ -0,0 +1,55 @@
+package printer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestConfigDir(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", "/tmp/xdg")
+
+	dir, err := ConfigDir()
+	if err != nil {
+		t.Fatalf("ConfigDir() error = %v", err)
+	}
+	if want := filepath.Join("/tmp/xdg", "epson-printing"); dir != want {
+		t.Errorf("ConfigDir() = %s, want %s", dir, want)
+	}
+}
+
+func TestConfig_SaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "epson-printing", ConfigFileName)
+
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig() on missing file error = %v", err)
+	}
+	if cfg.PrinterURI != "" {
+		t.Errorf("PrinterURI = %q, want empty", cfg.PrinterURI)
+	}
+
+	cfg.PrinterURI = "ipps://EPSON6A3B2C.local/ipp/print"
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	loaded, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if loaded.PrinterURI != cfg.PrinterURI || loaded.Path != path {
+		t.Errorf("LoadConfig() = %+v, want %+v", loaded, cfg)
+	}
+}
+
+func TestLoadConfig_Invalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ConfigFileName)
+	if err := os.WriteFile(path, []byte("{not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := LoadConfig(path); err == nil {
+		t.Error("expected error for invalid config file")
+	}
+}
@@ -0,0 +1,164 @@
+package printer
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DNS-SD service types browsed by Discover
+const (
+	ServiceIPP  = "_ipp._tcp"
+	ServiceIPPS = "_ipps._tcp"
+)
+
+// DefaultDiscoverTimeout is how long Discover listens for printers when
+// no timeout is given
+const DefaultDiscoverTimeout = 3 * time.Second
+
+// ServiceEntry is a DNS-SD service instance found by a Resolver
+type ServiceEntry struct {
+	Instance string            // Instance name, e.g. "EPSON ET-8550 Series"
+	Service  string            // Service type, e.g. "_ipp._tcp"
+	Host     string            // Target host name, e.g. "EPSON123456.local."
+	Port     int               // Target port
+	Addrs    []netip.Addr      // Addresses of the host, if known
+	TXT      map[string]string // TXT record keys and values; keys are lowercase
+}
+
+// Resolver browses the network for DNS-SD service instances. MDNSResolver
+// browses via multicast DNS; tests can inject a stand-in.
+type Resolver interface {
+	Browse(ctx context.Context, service string) ([]ServiceEntry, error)
+}
+
+// DiscoveredPrinter is a printer advertised on the network
+type DiscoveredPrinter struct {
+	Name    string   `json:"name"`              // Service instance name
+	URI     string   `json:"uri"`               // ipp:// or ipps:// printer URI
+	Model   string   `json:"model,omitempty"`   // Make and model from the ty key
+	Formats []string `json:"formats,omitempty"` // Document formats from the pdl key
+	Color   bool     `json:"color"`             // Color key is T
+	UUID    string   `json:"uuid,omitempty"`    // Printer UUID, shared by its ipp and ipps services
+	Secure  bool     `json:"secure"`            // Advertised as _ipps._tcp
+}
+
+// Discover browses the local network via mDNS for IPP printers
+// (_ipp._tcp and _ipps._tcp) for the given time and returns them as
+// candidate printer URIs
+func Discover(ctx context.Context, timeout time.Duration) ([]DiscoveredPrinter, error) {
+	return DiscoverWith(ctx, &MDNSResolver{}, timeout)
+}
+
+// DiscoverWith is Discover with a custom resolver. Printers are sorted by
+// name with the secure ipps:// URI of a printer first.
+func DiscoverWith(ctx context.Context, resolver Resolver, timeout time.Duration) ([]DiscoveredPrinter, error) {
+	if timeout <= 0 {
+		timeout = DefaultDiscoverTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	services := []string{ServiceIPPS, ServiceIPP}
+	entries := make([][]ServiceEntry, len(services))
+	errs := make([]error, len(services))
+
+	var wg sync.WaitGroup
+	for i, service := range services {
+		wg.Go(func() {
+			entries[i], errs[i] = resolver.Browse(ctx, service)
+		})
+	}
+	wg.Wait()
+
+	var printers []DiscoveredPrinter
+	for _, list := range entries {
+		for _, entry := range list {
+			if p, ok := printerFromEntry(entry); ok {
+				printers = append(printers, p)
+			}
+		}
+	}
+	if len(printers) == 0 {
+		if err := errors.Join(errs...); err != nil {
+			return nil, fmt.Errorf("discovering printers: %w", err)
+		}
+	}
+
+	slices.SortStableFunc(printers, func(a, b DiscoveredPrinter) int {
+		if c := cmp.Compare(a.Name, b.Name); c != 0 {
+			return c
+		}
+		if a.Secure != b.Secure {
+			if a.Secure {
+				return -1
+			}
+			return 1
+		}
+		return cmp.Compare(a.URI, b.URI)
+	})
+	return slices.CompactFunc(printers, func(a, b DiscoveredPrinter) bool {
+		return a.URI == b.URI
+	}), nil
+}
+
+// printerFromEntry builds a printer from a service entry and its TXT
+// record (PWG 5100.14, section 6). Entries without a host are skipped.
+func printerFromEntry(entry ServiceEntry) (DiscoveredPrinter, bool) {
+	host := strings.TrimSuffix(entry.Host, ".")
+	if host == "" && len(entry.Addrs) > 0 {
+		host = entry.Addrs[0].String()
+	}
+	if host == "" {
+		return DiscoveredPrinter{}, false
+	}
+
+	secure := entry.Service == ServiceIPPS
+	scheme, defaultPort := "ipp", 631
+	if secure {
+		scheme = "ipps"
+	}
+	if entry.Port != 0 && entry.Port != defaultPort {
+		host = net.JoinHostPort(host, strconv.Itoa(entry.Port))
+	} else if strings.Contains(host, ":") {
+		host = "[" + host + "]" // IPv6 address without port
+	}
+
+	p := DiscoveredPrinter{
+		Name:   entry.Instance,
+		URI:    fmt.Sprintf("%s://%s/%s", scheme, host, strings.TrimPrefix(entry.TXT["rp"], "/")),
+		Model:  entry.TXT["ty"],
+		Color:  strings.EqualFold(entry.TXT["color"], "T"),
+		UUID:   entry.TXT["uuid"],
+		Secure: secure,
+	}
+	if pdl := entry.TXT["pdl"]; pdl != "" {
+		p.Formats = strings.Split(pdl, ",")
+	}
+	return p, true
+}
+
+// parseTXT splits TXT record strings into lowercase keys and values.
+// Keys without "=" are boolean attributes and get an empty value.
+func parseTXT(records []string) map[string]string {
+	txt := make(map[string]string, len(records))
+	for _, record := range records {
+		key, value, _ := strings.Cut(record, "=")
+		if key == "" {
+			continue
+		}
+		key = strings.ToLower(key)
+		if _, seen := txt[key]; !seen { // RFC 6763, 6.4: first occurrence wins
+			txt[key] = value
+		}
+	}
+	return txt
+}
@@ -0,0 +1,195 @@
+package printer
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/netip"
+	"reflect"
+	"testing"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// fakeResolver returns fixed service entries per service type
+type fakeResolver map[string][]ServiceEntry
+
+func (r fakeResolver) Browse(_ context.Context, service string) ([]ServiceEntry, error) {
+	return r[service], nil
+}
+
+// failingResolver fails every browse
+type failingResolver struct{}
+
+func (failingResolver) Browse(context.Context, string) ([]ServiceEntry, error) {
+	return nil, errors.New("network unreachable")
+}
+
+func TestDiscoverWith(t *testing.T) {
+	txt := map[string]string{
+		"rp":    "ipp/print",
+		"ty":    "EPSON ET-8550 Series",
+		"pdl":   "application/pdf,image/jpeg,image/pwg-raster",
+		"color": "T",
+		"uuid":  "cfe92100-67c4-11d4-a45f-e0bb9e6a3b2c",
+	}
+	resolver := fakeResolver{
+		ServiceIPP: {
+			{Instance: "EPSON ET-8550 Series", Service: ServiceIPP, Host: "EPSON6A3B2C.local.", Port: 631, TXT: txt},
+			{Instance: "Office", Service: ServiceIPP, Host: "cups.local.", Port: 8631,
+				TXT: map[string]string{"rp": "printers/ET-2850", "color": "F"}},
+			{Instance: "No host", Service: ServiceIPP, Port: 631},
+		},
+		ServiceIPPS: {
+			{Instance: "EPSON ET-8550 Series", Service: ServiceIPPS, Host: "EPSON6A3B2C.local.", Port: 631, TXT: txt},
+		},
+	}
+
+	printers, err := DiscoverWith(context.Background(), resolver, time.Second)
+	if err != nil {
+		t.Fatalf("DiscoverWith() error = %v", err)
+	}
+
+	want := []DiscoveredPrinter{
+		{
+			Name:    "EPSON ET-8550 Series",
+			URI:     "ipps://EPSON6A3B2C.local/ipp/print",
+			Model:   "EPSON ET-8550 Series",
+			Formats: []string{FormatPDF, FormatJPEG, FormatPWGRaster},
+			Color:   true,
+			UUID:    "cfe92100-67c4-11d4-a45f-e0bb9e6a3b2c",
+			Secure:  true,
+		},
+		{
+			Name:    "EPSON ET-8550 Series",
+			URI:     "ipp://EPSON6A3B2C.local/ipp/print",
+			Model:   "EPSON ET-8550 Series",
+			Formats: []string{FormatPDF, FormatJPEG, FormatPWGRaster},
+			Color:   true,
+			UUID:    "cfe92100-67c4-11d4-a45f-e0bb9e6a3b2c",
+		},
+		{Name: "Office", URI: "ipp://cups.local:8631/printers/ET-2850"},
+	}
+	if !reflect.DeepEqual(printers, want) {
+		t.Errorf("DiscoverWith() =\n%+v\nwant\n%+v", printers, want)
+	}
+}
+
+func TestDiscoverWith_Errors(t *testing.T) {
+	if _, err := DiscoverWith(context.Background(), failingResolver{}, time.Second); err == nil {
+		t.Error("expected error when every browse fails")
+	}
+
+	printers, err := DiscoverWith(context.Background(), fakeResolver{}, time.Second)
+	if err != nil || len(printers) != 0 {
+		t.Errorf("DiscoverWith() = %v, %v; want no printers and no error", printers, err)
+	}
+}
+
+func TestParseTXT(t *testing.T) {
+	got := parseTXT([]string{"txtvers=1", "RP=ipp/print", "rp=ignored", "Duplex", "=bad", "note=a=b"})
+	want := map[string]string{"txtvers": "1", "rp": "ipp/print", "duplex": "", "note": "a=b"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseTXT() = %v, want %v", got, want)
+	}
+}
+
+// Test helper: answer mDNS queries on a local UDP port like an mDNS
+// responder that only sends the PTR record with its answer, so the
+// resolver has to ask for SRV and TXT separately
+func newTestMDNSResponder(t *testing.T) string {
+	t.Helper()
+
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+
+	service := dnsmessage.MustNewName("_ipp._tcp.local.")
+	instance := dnsmessage.MustNewName("EPSON ET-8550 Series._ipp._tcp.local.")
+	host := dnsmessage.MustNewName("EPSON6A3B2C.local.")
+	header := func(name dnsmessage.Name, typ dnsmessage.Type) dnsmessage.ResourceHeader {
+		return dnsmessage.ResourceHeader{Name: name, Type: typ, Class: dnsmessage.ClassINET, TTL: 120}
+	}
+
+	go func() {
+		buf := make([]byte, 9000)
+		for {
+			n, from, err := conn.ReadFromUDP(buf)
+			if err != nil {
+				return
+			}
+			var query dnsmessage.Message
+			if err := query.Unpack(buf[:n]); err != nil || len(query.Questions) == 0 {
+				continue
+			}
+
+			resp := dnsmessage.Message{Header: dnsmessage.Header{Response: true, Authoritative: true}}
+			switch q := query.Questions[0]; q.Type {
+			case dnsmessage.TypePTR:
+				if q.Name != service {
+					continue
+				}
+				resp.Answers = append(resp.Answers, dnsmessage.Resource{
+					Header: header(service, dnsmessage.TypePTR),
+					Body:   &dnsmessage.PTRResource{PTR: instance},
+				})
+			case dnsmessage.TypeSRV:
+				resp.Answers = append(resp.Answers, dnsmessage.Resource{
+					Header: header(instance, dnsmessage.TypeSRV),
+					Body:   &dnsmessage.SRVResource{Target: host, Port: 631},
+				})
+				resp.Additionals = append(resp.Additionals, dnsmessage.Resource{
+					Header: header(host, dnsmessage.TypeA),
+					Body:   &dnsmessage.AResource{A: [4]byte{192, 168, 1, 20}},
+				})
+			case dnsmessage.TypeTXT:
+				resp.Answers = append(resp.Answers, dnsmessage.Resource{
+					Header: header(instance, dnsmessage.TypeTXT),
+					Body:   &dnsmessage.TXTResource{TXT: []string{"rp=ipp/print", "ty=EPSON ET-8550 Series", "Color=T"}},
+				})
+			}
+
+			packet, err := resp.Pack()
+			if err == nil {
+				_, _ = conn.WriteToUDP(packet, from)
+			}
+		}
+	}()
+
+	return conn.LocalAddr().String()
+}
+
+func TestMDNSResolver_Browse(t *testing.T) {
+	resolver := &MDNSResolver{Addr: newTestMDNSResponder(t)}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	entries, err := resolver.Browse(ctx, ServiceIPP)
+	if err != nil {
+		t.Fatalf("Browse() error = %v", err)
+	}
+
+	want := []ServiceEntry{{
+		Instance: "EPSON ET-8550 Series",
+		Service:  ServiceIPP,
+		Host:     "EPSON6A3B2C.local.",
+		Port:     631,
+		Addrs:    []netip.Addr{netip.MustParseAddr("192.168.1.20")},
+		TXT:      map[string]string{"rp": "ipp/print", "ty": "EPSON ET-8550 Series", "color": "T"},
+	}}
+	if !reflect.DeepEqual(entries, want) {
+		t.Errorf("Browse() =\n%+v\nwant\n%+v", entries, want)
+	}
+
+	// Nothing advertises _ipps._tcp
+	ctx, cancel = context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if entries, err := resolver.Browse(ctx, ServiceIPPS); err != nil || len(entries) != 0 {
+		t.Errorf("Browse(%s) = %v, %v; want nothing", ServiceIPPS, entries, err)
+	}
+}
@@ -0,0 +1,186 @@
+package printer
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/go-pdf/fpdf"
+)
+
+// Document formats (MIME types) understood by PrintDocument
+const (
+	FormatPDF       = "application/pdf"
+	FormatJPEG      = "image/jpeg"
+	FormatPNG       = "image/png"
+	FormatTIFF      = "image/tiff"
+	FormatPWGRaster = "image/pwg-raster"
+)
+
+// imageMargin is the margin in mm around images printed on bordered paper sizes
+const imageMargin = 5.0
+
+// sniffLength is the number of leading bytes inspected by DetectFormat
+const sniffLength = 512
+
+// PrintDocument sends a PDF or image file to the printer via IPP
+func PrintDocument(printerURI, path string, opts PrintOptions) (*JobResult, error) {
+	return NewClient(printerURI).PrintDocument(context.Background(), path, opts)
+}
+
+// PrintDocument sends a PDF or image file to the printer.
+// Images are sent natively when the printer lists their format in
+// document-format-supported; otherwise JPEG and PNG images are wrapped
+// into a single-page PDF sized to opts.PaperSize. A dry run does not contact
+// the printer and describes images as sent natively.
+func (c *Client) PrintDocument(ctx context.Context, path string, opts PrintOptions) (*JobResult, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening document: %w", err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	// Peek at the start of the file without consuming it
+	reader := bufio.NewReaderSize(file, sniffLength)
+	head, err := reader.Peek(sniffLength)
+	if err != nil && err != io.EOF {
+		return nil, fmt.Errorf("reading document: %w", err)
+	}
+
+	format := DetectFormat(head)
+	switch format {
+	case FormatPDF:
+		return c.Print(ctx, jobName(path), format, reader, opts)
+	case FormatJPEG, FormatPNG, FormatTIFF, FormatPWGRaster:
+		// Handled below
+	default:
+		return nil, fmt.Errorf("unsupported document format: %s", format)
+	}
+
+	native, err := c.supportsFormat(ctx, format)
+	if err != nil {
+		return nil, err
+	}
+	if native {
+		return c.Print(ctx, jobName(path), format, reader, opts)
+	}
+
+	// Wrapping needs the whole image in memory
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("reading document: %w", err)
+	}
+	pdfData, err := imageToPDF(data, format, opts.PaperSize)
+	if err != nil {
+		return nil, err
+	}
+	return c.Print(ctx, jobName(path), FormatPDF, bytes.NewReader(pdfData), opts)
+}
+
+// supportsFormat reports whether the printer accepts an image format
+// natively. A dry run does not ask the printer; it notes on DryRunOutput
+// that the image is described as sent natively.
+func (c *Client) supportsFormat(ctx context.Context, format string) (bool, error) {
+	if c.DryRun {
+		_, err := fmt.Fprintf(c.dryRunOutput(),
+			"Note: a dry run does not ask the printer whether it accepts %s.\n"+
+				"The image is shown as sent natively; a printer without %s support gets it wrapped into a PDF.\n",
+			format, format)
+		if err != nil {
+			return false, fmt.Errorf("writing dry run output: %w", err)
+		}
+		return true, nil
+	}
+
+	caps, err := c.Capabilities(ctx)
+	if err != nil {
+		return false, err
+	}
+	return caps.SupportsFormat(format), nil
+}
+
+// DetectFormat sniffs the MIME type of a document from its first bytes
+func DetectFormat(data []byte) string {
+	switch {
+	case bytes.HasPrefix(data, []byte("RaS2")):
+		return FormatPWGRaster
+	case bytes.HasPrefix(data, []byte("II*\x00")), bytes.HasPrefix(data, []byte("MM\x00*")):
+		return FormatTIFF
+	}
+
+	return http.DetectContentType(data)
+}
+
+// imageToPDF wraps a JPEG or PNG image into a single-page PDF of the given
+// paper size. The page is rotated to match the image orientation and the
+// image is scaled to cover the page when borderless, or to fit within a
+// small margin otherwise.
+func imageToPDF(data []byte, format, paperName string) ([]byte, error) {
+	var imageType string
+	switch format {
+	case FormatJPEG:
+		imageType = "JPG"
+	case FormatPNG:
+		imageType = "PNG"
+	default:
+		return nil, fmt.Errorf("printer does not accept %s and it cannot be converted to PDF", format)
+	}
+
+	paper, borderless, ok := lookupPaperSize(paperName)
+	if !ok {
+		return nil, fmt.Errorf("unknown paper size for image conversion: %s", paperName)
+	}
+
+	pdf := fpdf.NewCustom(&fpdf.InitType{
+		UnitStr: "mm",
+		Size:    fpdf.SizeType{Wd: paper.Width, Ht: paper.Height},
+	})
+	pdf.SetMargins(0, 0, 0)
+	pdf.SetAutoPageBreak(false, 0)
+
+	imageOpts := fpdf.ImageOptions{ImageType: imageType}
+	img := pdf.RegisterImageOptionsReader("document", imageOpts, bytes.NewReader(data))
+	if err := pdf.Error(); err != nil {
+		return nil, fmt.Errorf("reading image: %w", err)
+	}
+
+	// Match page orientation to the image
+	orientation := "P"
+	pageWidth, pageHeight := paper.Width, paper.Height
+	if img.Width() > img.Height() {
+		orientation = "L"
+		pageWidth, pageHeight = paper.Height, paper.Width
+	}
+	pdf.AddPageFormat(orientation, fpdf.SizeType{Wd: paper.Width, Ht: paper.Height})
+
+	margin := imageMargin
+	if borderless {
+		margin = 0
+	}
+	areaWidth := pageWidth - 2*margin
+	areaHeight := pageHeight - 2*margin
+
+	// Scale to cover the page for borderless prints, otherwise fit inside it
+	scale := min(areaWidth/img.Width(), areaHeight/img.Height())
+	if borderless {
+		scale = max(areaWidth/img.Width(), areaHeight/img.Height())
+	}
+	width := img.Width() * scale
+	height := img.Height() * scale
+	x := (pageWidth - width) / 2
+	y := (pageHeight - height) / 2
+
+	pdf.ImageOptions("document", x, y, width, height, false, imageOpts, 0, "")
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, fmt.Errorf("generating PDF: %w", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,112 @@
+package printer
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+// Test helper: encode a solid-color test image of the given size
+func createTestImage(t *testing.T, format string, width, height int) []byte {
+	t.Helper()
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			img.Set(x, y, color.RGBA{R: 41, G: 128, B: 185, A: 255})
+		}
+	}
+
+	var buf bytes.Buffer
+	var err error
+	switch format {
+	case FormatJPEG:
+		err = jpeg.Encode(&buf, img, nil)
+	case FormatPNG:
+		err = png.Encode(&buf, img)
+	default:
+		t.Fatalf("unsupported test image format: %s", format)
+	}
+	if err != nil {
+		t.Fatalf("encoding test image: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestDetectFormat(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     []byte
+		expected string
+	}{
+		{"pdf", []byte("%PDF-1.7\n%\xe2\xe3\xcf\xd3\n"), FormatPDF},
+		{"jpeg", createTestImage(t, FormatJPEG, 4, 4), FormatJPEG},
+		{"png", createTestImage(t, FormatPNG, 4, 4), FormatPNG},
+		{"tiff little-endian", []byte("II*\x00\x08\x00\x00\x00"), FormatTIFF},
+		{"tiff big-endian", []byte("MM\x00*\x00\x00\x00\x08"), FormatTIFF},
+		{"pwg raster", []byte("RaS2PwgRaster\x00"), FormatPWGRaster},
+		{"plain text", []byte("hello printer"), "text/plain; charset=utf-8"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectFormat(tt.data); got != tt.expected {
+				t.Errorf("DetectFormat() = %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestImageToPDF(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		width  int
+		height int
+		paper  string
+	}{
+		{"portrait jpeg on 4x6 borderless", FormatJPEG, 40, 60, "4x6.Borderless"},
+		{"landscape jpeg on A4", FormatJPEG, 60, 40, "A4"},
+		{"png on A3+ borderless", FormatPNG, 33, 48, "13x19.Borderless"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := createTestImage(t, tt.format, tt.width, tt.height)
+
+			pdfData, err := imageToPDF(data, tt.format, tt.paper)
+			if err != nil {
+				t.Fatalf("imageToPDF() error = %v", err)
+			}
+			if DetectFormat(pdfData) != FormatPDF {
+				t.Errorf("imageToPDF() did not produce a PDF")
+			}
+		})
+	}
+}
+
+func TestImageToPDF_Errors(t *testing.T) {
+	jpegData := createTestImage(t, FormatJPEG, 4, 4)
+
+	tests := []struct {
+		name   string
+		data   []byte
+		format string
+		paper  string
+	}{
+		{"tiff cannot be converted", []byte("II*\x00"), FormatTIFF, "A4"},
+		{"unknown paper size", jpegData, FormatJPEG, "A0.Borderless"},
+		{"corrupt image", []byte("not a jpeg"), FormatJPEG, "A4"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := imageToPDF(tt.data, tt.format, tt.paper); err == nil {
+				t.Error("expected error, got nil")
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package printer
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/OpenPrinting/goipp"
+)
+
+// describeRequest writes every attribute of a request with its tag and
+// value, followed by the document size, to DryRunOutput without contacting
+// the printer. The raw request is written to DumpIPP if set.
+func (c *Client) describeRequest(msg *goipp.Message, document io.Reader) error {
+	header, err := msg.EncodeBytes()
+	if err != nil {
+		return fmt.Errorf("encoding IPP request: %w", err)
+	}
+	target, err := transportURL(c.URI)
+	if err != nil {
+		return err
+	}
+
+	// Read the document to measure it, dumping it along with the header
+	dump := io.Discard
+	if c.DumpIPP != nil {
+		dump = c.DumpIPP
+		if _, err := dump.Write(header); err != nil {
+			return fmt.Errorf("writing IPP dump: %w", err)
+		}
+	}
+	var size int64
+	if document != nil {
+		if size, err = io.Copy(dump, document); err != nil {
+			return fmt.Errorf("reading document: %w", err)
+		}
+	}
+
+	f := goipp.NewFormatter()
+	f.Printf("Dry run: %s request for %s (not sent)", goipp.Op(msg.Code), target)
+	f.FmtRequest(msg)
+	f.Printf("Document: %d bytes (request total: %d bytes)", size, int64(len(header))+size)
+	if _, err := f.WriteTo(c.dryRunOutput()); err != nil {
+		return fmt.Errorf("writing dry run output: %w", err)
+	}
+	return nil
+}
+
+// dryRunOutput returns DryRunOutput, or os.Stdout when it is unset
+func (c *Client) dryRunOutput() io.Writer {
+	if c.DryRunOutput == nil {
+		return os.Stdout
+	}
+	return c.DryRunOutput
+}
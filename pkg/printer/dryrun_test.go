@@ -0,0 +1,142 @@
+package printer
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/OpenPrinting/goipp"
+)
+
+func TestClient_Print_DryRun(t *testing.T) {
+	// Any request reaching the server fails the test
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		t.Error("dry run contacted the printer")
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	t.Cleanup(server.Close)
+
+	document := bytes.Repeat([]byte("%PDF-1.7\n"), 100)
+	var out, dump bytes.Buffer
+	c := NewClient(server.URL)
+	c.DryRun = true
+	c.DryRunOutput = &out
+	c.DumpIPP = &dump
+
+	opts := MustGetPrintOptions(ProfilePhoto4x6BorderlessGlossy)
+	opts.PageRange = "1-3,7"
+	result, err := c.Print(context.Background(), "photo.pdf", FormatPDF, bytes.NewReader(document), opts)
+	if err != nil {
+		t.Fatalf("Print() error = %v", err)
+	}
+	if result.JobID != 0 {
+		t.Errorf("dry run job ID = %d, want 0", result.JobID)
+	}
+
+	output := out.String()
+	for _, want := range []string{
+		"Dry run: Print-Job request for " + server.URL,
+		`ATTR "job-name" nameWithoutLanguage: photo.pdf`,
+		`ATTR "document-format" mimeMediaType: application/pdf`,
+		`MEMBER "media-size-name" keyword: na_index-4x6_4x6in`,
+		`MEMBER "media-source" keyword: photo`,
+		`ATTR "print-quality" enum: 5`,
+		`ATTR "page-ranges" rangeOfInteger: 1-3 7-7`,
+		"Document: 900 bytes",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("dry run output missing %q:\n%s", want, output)
+		}
+	}
+
+	// The dump is the encoded request followed by the document
+	var msg goipp.Message
+	reader := bytes.NewReader(dump.Bytes())
+	if err := msg.Decode(reader); err != nil {
+		t.Fatalf("decoding dump: %v", err)
+	}
+	if goipp.Op(msg.Code) != goipp.OpPrintJob {
+		t.Errorf("dumped operation = %s, want Print-Job", goipp.Op(msg.Code))
+	}
+	if rest, _ := io.ReadAll(reader); !bytes.Equal(rest, document) {
+		t.Errorf("dumped document = %d bytes, want %d", len(rest), len(document))
+	}
+}
+
+func TestClient_PrintDocument_DryRunImage(t *testing.T) {
+	// Any request reaching the server fails the test
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		t.Error("dry run contacted the printer")
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name   string
+		format string
+		file   string
+	}{
+		{name: "PNG", format: FormatPNG, file: "photo.png"},
+		{name: "JPEG", format: FormatJPEG, file: "photo.jpg"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.file)
+			if err := os.WriteFile(path, createTestImage(t, tt.format, 60, 40), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			var out bytes.Buffer
+			c := NewClient(server.URL)
+			c.DryRun = true
+			c.DryRunOutput = &out
+
+			if _, err := c.PrintDocument(context.Background(), path, DefaultPrintOptions()); err != nil {
+				t.Fatalf("PrintDocument() error = %v", err)
+			}
+
+			// The image is described as sent natively, with a note that the
+			// printer was not asked
+			if want := "mimeMediaType: " + tt.format; !strings.Contains(out.String(), want) {
+				t.Errorf("dry run output missing %q:\n%s", want, out.String())
+			}
+			if !strings.Contains(out.String(), "does not ask the printer") {
+				t.Errorf("dry run output missing the note about the format:\n%s", out.String())
+			}
+		})
+	}
+}
+
+func TestClient_Print_DumpIPP(t *testing.T) {
+	var received []byte
+	server := newTestPrintServer(t, func(_ *http.Request, _ *goipp.Message, doc io.Reader) {
+		received, _ = io.ReadAll(doc)
+	})
+
+	document := []byte("%PDF-1.7 dumped")
+	var dump bytes.Buffer
+	c := NewClient(server.URL)
+	c.DumpIPP = &dump
+
+	if _, err := c.Print(context.Background(), "doc.pdf", FormatPDF, bytes.NewReader(document), DefaultPrintOptions()); err != nil {
+		t.Fatalf("Print() error = %v", err)
+	}
+
+	if !bytes.Equal(received, document) {
+		t.Errorf("printer received %q, want %q", received, document)
+	}
+	if !bytes.HasSuffix(dump.Bytes(), document) {
+		t.Error("dump does not end with the document")
+	}
+	var msg goipp.Message
+	if err := msg.DecodeBytes(dump.Bytes()); err != nil {
+		t.Errorf("dump does not start with a valid IPP request: %v", err)
+	}
+}
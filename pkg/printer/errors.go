@@ -0,0 +1,96 @@
+package printer
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/OpenPrinting/goipp"
+)
+
+// IPPError is returned when the printer rejects a request, either with an
+// IPP error status or, before any IPP response, with an HTTP error such as
+// the 401 or 404 pages served by CUPS. Use errors.As to inspect it, or the
+// Is* helpers for common cases.
+type IPPError struct {
+	Op         goipp.Op     // Operation of the failed request
+	Status     goipp.Status // IPP status code; 0 when the HTTP request failed
+	Message    string       // status-message sent by the printer, if any
+	HTTPStatus int          // HTTP status code of the response
+}
+
+// Error describes the failure, e.g.
+// "printer returned error: client-error-not-found (Get-Job-Attributes): No such job"
+func (e *IPPError) Error() string {
+	var msg string
+	if e.HTTPStatus != http.StatusOK && e.HTTPStatus != 0 {
+		msg = fmt.Sprintf("printer returned HTTP %d %s (%s)",
+			e.HTTPStatus, http.StatusText(e.HTTPStatus), e.Op)
+	} else {
+		msg = fmt.Sprintf("printer returned error: %s (%s)", e.Status, e.Op)
+	}
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	return msg
+}
+
+// IsNotAuthorized reports whether err is an IPPError for missing or
+// rejected credentials
+func IsNotAuthorized(err error) bool {
+	return hasIPPError(err, []goipp.Status{
+		goipp.StatusErrorForbidden,
+		goipp.StatusErrorNotAuthenticated,
+		goipp.StatusErrorNotAuthorized,
+	}, http.StatusUnauthorized, http.StatusForbidden)
+}
+
+// IsPrinterBusy reports whether err is an IPPError for a printer that is
+// busy or temporarily unable to accept requests, so a later retry may work
+func IsPrinterBusy(err error) bool {
+	return hasIPPError(err, []goipp.Status{
+		goipp.StatusErrorBusy,
+		goipp.StatusErrorServiceUnavailable,
+		goipp.StatusErrorTemporary,
+	}, http.StatusServiceUnavailable)
+}
+
+// IsNotAcceptingJobs reports whether err is an IPPError for a printer whose
+// queue rejects new jobs. This is set by an administrator rather than
+// transient, so retrying does not help until the queue is enabled again.
+func IsNotAcceptingJobs(err error) bool {
+	return hasIPPError(err, []goipp.Status{
+		goipp.StatusErrorNotAcceptingJobs,
+	})
+}
+
+// IsDocumentFormatNotSupported reports whether err is an IPPError for a
+// document format the printer does not accept
+func IsDocumentFormatNotSupported(err error) bool {
+	return hasIPPError(err, []goipp.Status{
+		goipp.StatusErrorDocumentFormatNotSupported,
+	})
+}
+
+// IsNotFound reports whether err is an IPPError for an unknown job or
+// printer, including a wrong printer URI path
+func IsNotFound(err error) bool {
+	return hasIPPError(err, []goipp.Status{
+		goipp.StatusErrorNotFound,
+		goipp.StatusErrorGone,
+	}, http.StatusNotFound)
+}
+
+// hasIPPError reports whether err wraps an IPPError with one of the given
+// IPP or HTTP status codes
+func hasIPPError(err error, statuses []goipp.Status, httpStatuses ...int) bool {
+	var ippErr *IPPError
+	if !errors.As(err, &ippErr) {
+		return false
+	}
+	if ippErr.HTTPStatus != http.StatusOK && ippErr.HTTPStatus != 0 {
+		return slices.Contains(httpStatuses, ippErr.HTTPStatus)
+	}
+	return slices.Contains(statuses, ippErr.Status)
+}
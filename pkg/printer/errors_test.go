@@ -0,0 +1,147 @@
+package printer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/OpenPrinting/goipp"
+)
+
+func TestClient_IPPError(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      goipp.Status
+		httpStatus  int
+		message     string
+		wantIs      func(error) bool
+		wantMessage string
+	}{
+		{
+			name:        "not authorized",
+			status:      goipp.StatusErrorNotAuthorized,
+			message:     "Access denied",
+			wantIs:      IsNotAuthorized,
+			wantMessage: "printer returned error: client-error-not-authorized (Get-Printer-Attributes): Access denied",
+		},
+		{
+			name:        "busy",
+			status:      goipp.StatusErrorBusy,
+			wantIs:      IsPrinterBusy,
+			wantMessage: "printer returned error: server-error-busy (Get-Printer-Attributes)",
+		},
+		{
+			name:        "document format not supported",
+			status:      goipp.StatusErrorDocumentFormatNotSupported,
+			wantIs:      IsDocumentFormatNotSupported,
+			wantMessage: "client-error-document-format-not-supported",
+		},
+		{
+			name:        "not found",
+			status:      goipp.StatusErrorNotFound,
+			wantIs:      IsNotFound,
+			wantMessage: "client-error-not-found",
+		},
+		{
+			name:        "HTTP 401 page",
+			httpStatus:  http.StatusUnauthorized,
+			wantIs:      IsNotAuthorized,
+			wantMessage: "printer returned HTTP 401 Unauthorized (Get-Printer-Attributes)",
+		},
+		{
+			name:        "HTTP 404 page",
+			httpStatus:  http.StatusNotFound,
+			wantIs:      IsNotFound,
+			wantMessage: "HTTP 404 Not Found",
+		},
+		{
+			name:        "HTTP 503",
+			httpStatus:  http.StatusServiceUnavailable,
+			wantIs:      IsPrinterBusy,
+			wantMessage: "HTTP 503 Service Unavailable",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.httpStatus != 0 {
+					// CUPS sends an HTML page that is not an IPP response
+					w.Header().Set("Content-Type", "text/html")
+					w.WriteHeader(tt.httpStatus)
+					_, _ = fmt.Fprint(w, "<html><body>Error</body></html>")
+					return
+				}
+				ippHandler(func(_ *http.Request, req *goipp.Message) *goipp.Message {
+					resp := goipp.NewResponse(goipp.DefaultVersion, tt.status, req.RequestID)
+					if tt.message != "" {
+						resp.Operation.Add(goipp.MakeAttr("status-message",
+							goipp.TagText, goipp.String(tt.message)))
+					}
+					return resp
+				}).ServeHTTP(w, r)
+			}))
+			t.Cleanup(server.Close)
+
+			_, err := NewClient(server.URL).Attributes(context.Background())
+			if err == nil {
+				t.Fatal("expected error, got nil")
+			}
+
+			var ippErr *IPPError
+			if !errors.As(err, &ippErr) {
+				t.Fatalf("expected *IPPError, got %T: %v", err, err)
+			}
+			if ippErr.Op != goipp.OpGetPrinterAttributes {
+				t.Errorf("Op = %s, want Get-Printer-Attributes", ippErr.Op)
+			}
+			if tt.httpStatus != 0 && ippErr.HTTPStatus != tt.httpStatus {
+				t.Errorf("HTTPStatus = %d, want %d", ippErr.HTTPStatus, tt.httpStatus)
+			}
+			if tt.httpStatus == 0 && ippErr.Status != tt.status {
+				t.Errorf("Status = %s, want %s", ippErr.Status, tt.status)
+			}
+			if !tt.wantIs(err) {
+				t.Errorf("helper returned false for %v", err)
+			}
+			if !strings.Contains(err.Error(), tt.wantMessage) {
+				t.Errorf("error = %q, want it to contain %q", err, tt.wantMessage)
+			}
+			if strings.Contains(err.Error(), "decoding response") {
+				t.Errorf("error = %q, should not be a decoding error", err)
+			}
+		})
+	}
+}
+
+func TestIPPErrorHelpers(t *testing.T) {
+	busy := fmt.Errorf("printing PDF: %w", &IPPError{Op: goipp.OpPrintJob, Status: goipp.StatusErrorBusy, HTTPStatus: http.StatusOK})
+
+	tests := []struct {
+		name string
+		is   func(error) bool
+		err  error
+		want bool
+	}{
+		{"wrapped busy", IsPrinterBusy, busy, true},
+		{"busy is not not-found", IsNotFound, busy, false},
+		{"plain error", IsNotAuthorized, errors.New("connection refused"), false},
+		{"nil error", IsPrinterBusy, nil, false},
+		{"forbidden", IsNotAuthorized, &IPPError{Status: goipp.StatusErrorForbidden}, true},
+		{"HTTP 500 is not busy", IsPrinterBusy, &IPPError{HTTPStatus: http.StatusInternalServerError}, false},
+		{"not accepting jobs", IsNotAcceptingJobs, &IPPError{Status: goipp.StatusErrorNotAcceptingJobs}, true},
+		{"not accepting jobs is not busy", IsPrinterBusy, &IPPError{Status: goipp.StatusErrorNotAcceptingJobs}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.is(tt.err); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
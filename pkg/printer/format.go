@@ -3,6 +3,8 @@ package printer
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
+	"time"
 )
 
 // Print displays the printer information to the console in a formatted way
@@ -18,10 +20,39 @@ func (p *Info) Print() {
 		fmt.Printf("Message: %s\n", p.StateMessage)
 	}
 
-	fmt.Println("\n--- INK LEVELS (6-COLOR SYSTEM) ---")
+	fmt.Printf("\n--- %s ---\n", ModelFor(p.Model).inkTitle(len(p.InkLevels)))
 	for _, ink := range p.InkLevels {
 		bar := createBar(ink.Level)
-		fmt.Printf("%-20s [%s] %3d%% (%s)\n", ink.Name, bar, ink.Level, ink.Color)
+		low := ""
+		if ink.Low() {
+			low = "  LOW"
+		}
+		fmt.Printf("%-20s [%s] %3d%% (%s)%s\n", ink.Name, bar, ink.Level, ink.Color, low)
+	}
+
+	p.Maintenance.print()
+}
+
+// print displays the maintenance box section, if the printer reports one
+func (m Maintenance) print() {
+	if m.Status == "" || m.Status == MaintenanceUnknown {
+		return
+	}
+
+	fmt.Println("\n--- MAINTENANCE BOX ---")
+	for _, box := range m.Boxes {
+		if box.Level < 0 {
+			fmt.Printf("%-20s level unknown\n", box.Name)
+			continue
+		}
+		fmt.Printf("%-20s [%s] %3d%% full\n", box.Name, createBar(box.Level), box.Level)
+	}
+	fmt.Printf("Status: %s\n", m.Status)
+	if len(m.Reasons) > 0 {
+		fmt.Printf("Reasons: %s\n", strings.Join(m.Reasons, ", "))
+	}
+	if advice := m.Advice(); advice != "" {
+		fmt.Printf("⚠ %s\n", advice)
 	}
 }
 
@@ -34,6 +65,82 @@ func (p *Info) ToJSON() (string, error) {
 	return string(data), nil
 }
 
+// Print displays the job status to the console in a formatted way
+func (j *Job) Print() {
+	fmt.Printf("--- JOB %d ---\n", j.ID)
+	if j.Name != "" {
+		fmt.Printf("Name: %s\n", j.Name)
+	}
+	if j.Owner != "" {
+		fmt.Printf("Owner: %s\n", j.Owner)
+	}
+	fmt.Printf("State: %s\n", j.State)
+	if len(j.StateReasons) > 0 {
+		fmt.Printf("State Reasons: %s\n", strings.Join(j.StateReasons, ", "))
+	}
+	if j.StateMessage != "" {
+		fmt.Printf("Message: %s\n", j.StateMessage)
+	}
+	fmt.Printf("Impressions Completed: %d\n", j.ImpressionsCompleted)
+	printTime("Created", j.TimeAtCreation)
+	printTime("Processing", j.TimeAtProcessing)
+	printTime("Completed", j.TimeAtCompleted)
+}
+
+// ToJSON returns the job status as a JSON string
+func (j *Job) ToJSON() (string, error) {
+	data, err := json.MarshalIndent(j, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshaling to JSON: %w", err)
+	}
+	return string(data), nil
+}
+
+// Print displays the jobs as a table
+func (jobs JobList) Print() {
+	if len(jobs) == 0 {
+		fmt.Println("No jobs")
+		return
+	}
+
+	fmt.Printf("%-6s %-12s %-30s %-11s %5s  %s\n",
+		"ID", "OWNER", "NAME", "STATE", "PAGES", "CREATED")
+	for _, j := range jobs {
+		created := ""
+		if !j.TimeAtCreation.IsZero() {
+			created = j.TimeAtCreation.Local().Format("2006-01-02 15:04")
+		}
+		fmt.Printf("%-6d %-12s %-30s %-11s %5d  %s\n",
+			j.ID, truncate(j.Owner, 12), truncate(j.Name, 30), j.State,
+			j.ImpressionsCompleted, created)
+	}
+}
+
+// ToJSON returns the jobs as a JSON array
+func (jobs JobList) ToJSON() (string, error) {
+	data, err := json.MarshalIndent(jobs, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshaling to JSON: %w", err)
+	}
+	return string(data), nil
+}
+
+// truncate shortens s to at most n characters, marking the cut with "…"
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n-1]) + "…"
+}
+
+// printTime prints a labeled timestamp, skipping unknown times
+func printTime(label string, t time.Time) {
+	if !t.IsZero() {
+		fmt.Printf("%s: %s\n", label, t.Local().Format("2006-01-02 15:04:05"))
+	}
+}
+
 // createBar creates a visual progress bar for ink levels
 func createBar(level int) string {
 	barLength := 20
@@ -219,3 +219,93 @@ func BenchmarkCreateBar(b *testing.B) {
 		createBar(75)
 	}
 }
+
+func TestJob_Print(t *testing.T) {
+	job := parseJob(createMockJobAttributes(42, JobCompleted))
+
+	defer func() {
+		if r := recover(); r != nil {
+			t.Errorf("Print() panicked: %v", r)
+		}
+	}()
+
+	job.Print()
+}
+
+func TestJob_ToJSON(t *testing.T) {
+	job := parseJob(createMockJobAttributes(42, JobCompleted))
+
+	jsonStr, err := job.ToJSON()
+	if err != nil {
+		t.Fatalf("ToJSON() error = %v", err)
+	}
+
+	var result map[string]interface{}
+	if err := json.Unmarshal([]byte(jsonStr), &result); err != nil {
+		t.Fatalf("ToJSON() produced invalid JSON: %v", err)
+	}
+
+	if result["state"] != "Completed" {
+		t.Errorf("expected state 'Completed', got %v", result["state"])
+	}
+	if _, exists := result["time_at_completed"]; exists {
+		t.Error("expected unknown completion time to be omitted")
+	}
+}
+
+func TestJobList_Print(t *testing.T) {
+	jobs := JobList{
+		*parseJob(createMockJobAttributes(41, JobProcessing)),
+		{ID: 42, Name: "a-very-long-job-name-that-does-not-fit-the-column.pdf", State: JobPending},
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			t.Errorf("Print() panicked: %v", r)
+		}
+	}()
+
+	jobs.Print()
+	JobList{}.Print()
+}
+
+func TestJobList_ToJSON(t *testing.T) {
+	jobs := JobList{*parseJob(createMockJobAttributes(41, JobProcessing))}
+
+	jsonStr, err := jobs.ToJSON()
+	if err != nil {
+		t.Fatalf("ToJSON() error = %v", err)
+	}
+
+	var result []map[string]interface{}
+	if err := json.Unmarshal([]byte(jsonStr), &result); err != nil {
+		t.Fatalf("ToJSON() produced invalid JSON: %v", err)
+	}
+	if len(result) != 1 || result[0]["state"] != "Processing" {
+		t.Errorf("unexpected JSON output: %s", jsonStr)
+	}
+
+	empty, _ := JobList{}.ToJSON()
+	if empty != "[]" {
+		t.Errorf("expected empty list to marshal as [], got %s", empty)
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		input    string
+		n        int
+		expected string
+	}{
+		{"short", 10, "short"},
+		{"exactly-10", 10, "exactly-10"},
+		{"much-too-long", 8, "much-to…"},
+		{"åäöåäöåäö", 4, "åäö…"},
+	}
+
+	for _, tt := range tests {
+		if got := truncate(tt.input, tt.n); got != tt.expected {
+			t.Errorf("truncate(%q, %d) = %q, expected %q", tt.input, tt.n, got, tt.expected)
+		}
+	}
+}
@@ -1,18 +1,24 @@
 package printer
 
 import (
-	"bytes"
+	"context"
 	"fmt"
-	"net/http"
+	"strings"
 
 	"github.com/OpenPrinting/goipp"
 )
 
 // InkLevel represents a single ink tank level
 type InkLevel struct {
-	Name  string `json:"name"`
-	Level int    `json:"level"`
-	Color string `json:"color"`
+	Name     string `json:"name"`
+	Level    int    `json:"level"`
+	Color    string `json:"color"`
+	LowLevel int    `json:"low_level,omitempty"` // Level at which the printer reports the tank as low
+}
+
+// Low reports whether the tank is at or below its low level
+func (i InkLevel) Low() bool {
+	return i.LowLevel > 0 && i.Level <= i.LowLevel
 }
 
 // Info contains all printer information and status
@@ -20,21 +26,42 @@ type Info struct {
 	Name         string     `json:"name"`
 	Model        string     `json:"model"`
 	State        string     `json:"state"`
-	StateReasons string     `json:"state_reasons"`
+	StateReasons string     `json:"state_reasons"` // Comma-separated printer-state-reasons
 	StateMessage string     `json:"state_message,omitempty"`
 	InkLevels    []InkLevel `json:"ink_levels"`
+
+	// Maintenance is the state of the maintenance box, which the ink
+	// markers do not include
+	Maintenance Maintenance `json:"maintenance"`
 }
 
 // GetPrinterInfo retrieves all printer information and status via IPP
 func GetPrinterInfo(printerURI string) (*Info, error) {
-	msg, err := queryPrinter(printerURI)
+	return NewClient(printerURI).Info(context.Background())
+}
+
+// GetPrinterInfoFor retrieves printer information from a registry entry,
+// using its credentials and TLS settings
+func GetPrinterInfoFor(entry PrinterEntry) (*Info, error) {
+	client, err := NewClientFor(entry)
 	if err != nil {
 		return nil, err
 	}
+	return client.Info(context.Background())
+}
 
+// Info retrieves all printer information and status
+func (c *Client) Info(ctx context.Context) (*Info, error) {
+	msg, err := c.Attributes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	reasons := getStringValues(msg, "printer-state-reasons")
 	info := &Info{
-		State:     getPrinterState(msg),
-		InkLevels: getInkLevels(msg),
+		State:       getPrinterState(msg),
+		InkLevels:   getInkLevels(msg),
+		Maintenance: newMaintenance(getMaintenanceBoxes(msg), reasons),
 	}
 
 	// Get optional string attributes
@@ -44,8 +71,8 @@ func GetPrinterInfo(printerURI string) (*Info, error) {
 	if attr := getAttribute(msg, "printer-make-and-model"); attr != nil && len(attr.Values) > 0 {
 		info.Model = fmt.Sprintf("%v", attr.Values[0].V)
 	}
-	if attr := getAttribute(msg, "printer-state-reasons"); attr != nil && len(attr.Values) > 0 {
-		info.StateReasons = fmt.Sprintf("%v", attr.Values[0].V)
+	if len(reasons) > 0 {
+		info.StateReasons = strings.Join(reasons, ", ")
 	}
 	if attr := getAttribute(msg, "printer-state-message"); attr != nil && len(attr.Values) > 0 {
 		info.StateMessage = fmt.Sprintf("%v", attr.Values[0].V)
@@ -54,47 +81,14 @@ func GetPrinterInfo(printerURI string) (*Info, error) {
 	return info, nil
 }
 
-// queryPrinter sends an IPP request to get printer attributes
-func queryPrinter(printerURI string) (*goipp.Message, error) {
-	// Build IPP Get-Printer-Attributes request
-	msg := goipp.NewRequest(goipp.DefaultVersion, goipp.OpGetPrinterAttributes, 1)
-	msg.Operation.Add(goipp.MakeAttr("attributes-charset",
-		goipp.TagCharset, goipp.String("utf-8")))
-	msg.Operation.Add(goipp.MakeAttr("attributes-natural-language",
-		goipp.TagLanguage, goipp.String("en-US")))
-	msg.Operation.Add(goipp.MakeAttr("printer-uri",
-		goipp.TagURI, goipp.String(printerURI)))
-	msg.Operation.Add(goipp.MakeAttr("requested-attributes",
-		goipp.TagKeyword, goipp.String("all")))
-
-	// Encode request
-	request, err := msg.EncodeBytes()
-	if err != nil {
-		return nil, fmt.Errorf("encoding request: %w", err)
-	}
-
-	// Send HTTP request
-	resp, err := http.Post(printerURI, goipp.ContentType, bytes.NewBuffer(request))
-	if err != nil {
-		return nil, fmt.Errorf("sending HTTP request: %w", err)
-	}
-	defer func() {
-		_ = resp.Body.Close()
-	}()
-
-	// Decode response
-	var respMsg goipp.Message
-	err = respMsg.Decode(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("decoding response: %w", err)
-	}
-
-	return &respMsg, nil
-}
-
 // getAttribute retrieves a specific attribute from the printer response
 func getAttribute(msg *goipp.Message, name string) *goipp.Attribute {
-	for _, attr := range msg.Printer {
+	return findAttribute(msg.Printer, name)
+}
+
+// findAttribute retrieves a specific attribute from an attribute group
+func findAttribute(attrs goipp.Attributes, name string) *goipp.Attribute {
+	for _, attr := range attrs {
 		if attr.Name == name {
 			return &attr
 		}
@@ -102,6 +96,20 @@ func getAttribute(msg *goipp.Message, name string) *goipp.Attribute {
 	return nil
 }
 
+// getStringValues returns all values of a printer attribute as strings
+func getStringValues(msg *goipp.Message, name string) []string {
+	attr := getAttribute(msg, name)
+	if attr == nil {
+		return nil
+	}
+
+	values := make([]string, 0, len(attr.Values))
+	for _, v := range attr.Values {
+		values = append(values, v.V.String())
+	}
+	return values
+}
+
 // getPrinterState returns the printer state as a human-readable string
 func getPrinterState(msg *goipp.Message) string {
 	attr := getAttribute(msg, "printer-state")
@@ -123,32 +131,90 @@ func getPrinterState(msg *goipp.Message) string {
 	return "Unknown"
 }
 
-// getInkLevels retrieves all ink tank levels from the printer
+// marker is one supply or waste receptacle in the marker-* attributes
+type marker struct {
+	name      string
+	color     string
+	kind      string // marker-types keyword, e.g. ink-cartridge or waste-ink
+	level     int
+	lowLevel  int
+	highLevel int
+}
+
+// getMarkers retrieves the markers of the printer. marker-names and
+// marker-levels are required; the other marker-* attributes are optional.
+func getMarkers(msg *goipp.Message) []marker {
+	names := getStringValues(msg, "marker-names")
+	levels := getIntValues(msg, "marker-levels")
+	colors := getStringValues(msg, "marker-colors")
+	types := getStringValues(msg, "marker-types")
+	lowLevels := getIntValues(msg, "marker-low-levels")
+	highLevels := getIntValues(msg, "marker-high-levels")
+
+	at := func(values []string, i int) string {
+		if i < len(values) {
+			return values[i]
+		}
+		return ""
+	}
+	atInt := func(values []int, i int) int {
+		if i < len(values) {
+			return values[i]
+		}
+		return 0
+	}
+
+	var markers []marker
+	for i, name := range names {
+		if i >= len(levels) {
+			break
+		}
+		markers = append(markers, marker{
+			name:      name,
+			color:     at(colors, i),
+			kind:      at(types, i),
+			level:     levels[i],
+			lowLevel:  atInt(lowLevels, i),
+			highLevel: atInt(highLevels, i),
+		})
+	}
+	return markers
+}
+
+// getInkLevels retrieves all ink tank levels from the printer, skipping
+// waste ink markers
 func getInkLevels(msg *goipp.Message) []InkLevel {
 	var levels []InkLevel
+	for _, m := range getMarkers(msg) {
+		if !isWasteMarker(m.kind) {
+			levels = append(levels, InkLevel{Name: m.name, Level: m.level, Color: m.color, LowLevel: m.lowLevel})
+		}
+	}
+	return levels
+}
 
-	namesAttr := getAttribute(msg, "marker-names")
-	levelsAttr := getAttribute(msg, "marker-levels")
-	colorsAttr := getAttribute(msg, "marker-colors")
-
-	if namesAttr != nil && levelsAttr != nil && colorsAttr != nil {
-		for i := range namesAttr.Values {
-			if i < len(levelsAttr.Values) && i < len(colorsAttr.Values) {
-				name := fmt.Sprintf("%v", namesAttr.Values[i].V)
-				var level int
-				if levelVal, ok := levelsAttr.Values[i].V.(goipp.Integer); ok {
-					level = int(levelVal)
-				}
-				color := fmt.Sprintf("%v", colorsAttr.Values[i].V)
-
-				levels = append(levels, InkLevel{
-					Name:  name,
-					Level: level,
-					Color: color,
-				})
-			}
+// getMaintenanceBoxes retrieves the waste ink markers from the printer
+func getMaintenanceBoxes(msg *goipp.Message) []MaintenanceBox {
+	var boxes []MaintenanceBox
+	for _, m := range getMarkers(msg) {
+		if isWasteMarker(m.kind) {
+			boxes = append(boxes, MaintenanceBox{Name: m.name, Level: m.level, HighLevel: m.highLevel})
 		}
 	}
+	return boxes
+}
 
-	return levels
+// getIntValues returns all integer values of a printer attribute
+func getIntValues(msg *goipp.Message, name string) []int {
+	attr := getAttribute(msg, name)
+	if attr == nil {
+		return nil
+	}
+
+	values := make([]int, 0, len(attr.Values))
+	for _, v := range attr.Values {
+		n, _ := v.V.(goipp.Integer)
+		values = append(values, int(n))
+	}
+	return values
 }
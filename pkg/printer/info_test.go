@@ -1,25 +1,23 @@
 package printer
 
 import (
+	"context"
 	"testing"
 
+	"github.com/Eric-Eklund/epson-printing/pkg/printer/ipptest"
 	"github.com/OpenPrinting/goipp"
 )
 
 // Test helper: create a mock IPP message with printer attributes
 func createMockMessage() *goipp.Message {
-	msg := &goipp.Message{
-		Printer: []goipp.Attribute{
-			goipp.MakeAttr("printer-info", goipp.TagText, goipp.String("Test Printer")),
-			goipp.MakeAttr("printer-make-and-model", goipp.TagText, goipp.String("Test Model")),
-			goipp.MakeAttr("printer-state", goipp.TagEnum, goipp.Integer(3)), // Idle
-			goipp.MakeAttr("printer-state-reasons", goipp.TagKeyword, goipp.String("none")),
-			goipp.MakeAttr("marker-names", goipp.TagName, goipp.String("Black"), goipp.String("Cyan")),
-			goipp.MakeAttr("marker-levels", goipp.TagInteger, goipp.Integer(80), goipp.Integer(60)),
-			goipp.MakeAttr("marker-colors", goipp.TagName, goipp.String("#000000"), goipp.String("#00FFFF")),
-		},
+	p := ipptest.NewPrinter()
+	p.Info = "Test Printer"
+	p.MakeAndModel = "Test Model"
+	p.Markers = []ipptest.Marker{
+		{Name: "Black", Color: "#000000", Level: 80},
+		{Name: "Cyan", Color: "#00FFFF", Level: 60},
 	}
-	return msg
+	return &goipp.Message{Printer: p.PrinterAttributes()}
 }
 
 func TestGetAttribute(t *testing.T) {
@@ -168,3 +166,18 @@ func BenchmarkGetInkLevels(b *testing.B) {
 		getInkLevels(msg)
 	}
 }
+
+func TestClient_Info_StateReasons(t *testing.T) {
+	p := ipptest.NewPrinter()
+	p.StateReasons = []string{"media-empty-error", "marker-supply-low-warning"}
+	server := ipptest.NewServer(p)
+	defer server.Close()
+
+	info, err := NewClient(server.URI).Info(context.Background())
+	if err != nil {
+		t.Fatalf("Info() error = %v", err)
+	}
+	if want := "media-empty-error, marker-supply-low-warning"; info.StateReasons != want {
+		t.Errorf("StateReasons = %q, want %q", info.StateReasons, want)
+	}
+}
@@ -0,0 +1,203 @@
+package printer
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// InkHistoryFileName is the name of the ink history file in DataDir
+const InkHistoryFileName = "ink-history.jsonl"
+
+// RefillThreshold is the smallest rise of an ink level, in percent, that
+// counts as a refill. Smaller rises are measurement noise.
+const RefillThreshold = 5
+
+// InkSnapshot is the ink levels of a printer at one point in time
+type InkSnapshot struct {
+	Time    time.Time  `json:"time"`
+	Printer string     `json:"printer,omitempty"` // Registry name or printer URI
+	URI     string     `json:"uri,omitempty"`     // Printer URI, also when Printer is a name
+	Model   string     `json:"model,omitempty"`   // printer-make-and-model
+	Levels  []InkLevel `json:"levels"`
+}
+
+// InkRefill is a rise of an ink level between two snapshots
+type InkRefill struct {
+	Time time.Time `json:"time"` // Time of the first snapshot after the refill
+	From int       `json:"from"`
+	To   int       `json:"to"`
+}
+
+// InkUsage is the consumption of one ink tank over a period
+type InkUsage struct {
+	Name    string      `json:"name"`
+	Color   string      `json:"color,omitempty"`
+	First   int         `json:"first"` // Level at the start of the period
+	Level   int         `json:"level"` // Level at the end of the period; negative when never known
+	Used    int         `json:"used"`  // Percent used, not counting refills
+	Refills []InkRefill `json:"refills,omitempty"`
+	PerDay  float64     `json:"per_day"` // Average percent used per day
+
+	// DaysLeft forecasts when the tank runs empty at the average rate;
+	// negative when nothing was used
+	DaysLeft float64 `json:"days_left"`
+}
+
+// InkHistory is an append-only store of ink level snapshots, one JSON
+// object per line
+type InkHistory struct {
+	Path string
+}
+
+// DefaultInkHistoryPath returns the path of the ink history in DataDir
+func DefaultInkHistoryPath() (string, error) {
+	dir, err := DataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, InkHistoryFileName), nil
+}
+
+// NewInkHistory returns the ink history stored at path. The file is
+// created by the first Record.
+func NewInkHistory(path string) *InkHistory {
+	return &InkHistory{Path: path}
+}
+
+// Record appends a snapshot to the history
+func (h *InkHistory) Record(snapshot InkSnapshot) error {
+	if snapshot.Time.IsZero() {
+		snapshot.Time = time.Now()
+	}
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return fmt.Errorf("encoding ink snapshot: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(h.Path), 0o755); err != nil {
+		return fmt.Errorf("creating data directory: %w", err)
+	}
+	file, err := os.OpenFile(h.Path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return fmt.Errorf("opening ink history: %w", err)
+	}
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("writing ink history: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("writing ink history: %w", err)
+	}
+	return nil
+}
+
+// Snapshots returns the snapshots of a printer taken at or after since, in
+// the order they were recorded. The printer matches the Printer or URI of a
+// snapshot, so a URI finds the snapshots recorded under a registry name
+// too. An empty printer matches every printer and a zero since returns the
+// whole history. A missing file is an empty history.
+func (h *InkHistory) Snapshots(printer string, since time.Time) ([]InkSnapshot, error) {
+	file, err := os.Open(h.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("opening ink history: %w", err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	var snapshots []InkSnapshot
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var s InkSnapshot
+		if err := json.Unmarshal(scanner.Bytes(), &s); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", h.Path, line, err)
+		}
+		if (printer == "" || s.Printer == printer || s.URI == printer) && !s.Time.Before(since) {
+			snapshots = append(snapshots, s)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading ink history: %w", err)
+	}
+	return snapshots, nil
+}
+
+// Usage returns the consumption of each ink tank of a printer since the
+// given time, in the tank order of the latest snapshot
+func (h *InkHistory) Usage(printer string, since time.Time) ([]InkUsage, error) {
+	snapshots, err := h.Snapshots(printer, since)
+	if err != nil {
+		return nil, err
+	}
+	return InkUsageOf(snapshots), nil
+}
+
+// InkUsageOf computes the consumption of each ink tank over a series of
+// snapshots. A level that rises by RefillThreshold or more is a refill;
+// every drop counts as ink used. Negative levels, which printers report
+// when a level is unknown, are skipped.
+func InkUsageOf(snapshots []InkSnapshot) []InkUsage {
+	if len(snapshots) == 0 {
+		return nil
+	}
+
+	latest := snapshots[len(snapshots)-1]
+	days := latest.Time.Sub(snapshots[0].Time).Hours() / 24
+
+	usage := make([]InkUsage, 0, len(latest.Levels))
+	for _, ink := range latest.Levels {
+		u := InkUsage{Name: ink.Name, Color: ink.Color, First: ink.Level, Level: ink.Level, DaysLeft: -1}
+
+		prev, seen := 0, false
+		for _, s := range snapshots {
+			level, ok := inkLevel(s.Levels, ink.Name)
+			if !ok {
+				continue
+			}
+			switch {
+			case !seen:
+				u.First, seen = level, true
+			case level < prev:
+				u.Used += prev - level
+			case level-prev >= RefillThreshold:
+				u.Refills = append(u.Refills, InkRefill{Time: s.Time, From: prev, To: level})
+			default:
+				continue // Unchanged or noise: keep comparing with the last real level
+			}
+			prev = level
+		}
+		if seen && u.Level < 0 {
+			u.Level = prev // Unknown now: use the last known level
+		}
+
+		if days > 0 && u.Used > 0 {
+			u.PerDay = float64(u.Used) / days
+			u.DaysLeft = math.Round(float64(u.Level)/u.PerDay*10) / 10
+		}
+		usage = append(usage, u)
+	}
+	return usage
+}
+
+// inkLevel returns the level of the named tank in a snapshot, if the
+// snapshot has the tank and its level is known
+func inkLevel(levels []InkLevel, name string) (int, bool) {
+	for _, l := range levels {
+		if l.Name == name {
+			return l.Level, l.Level >= 0
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,172 @@
+package printer
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// Test helper: a snapshot of Black and Cyan levels on a given day
+func inkSnapshot(printer string, day int, black, cyan int) InkSnapshot {
+	return InkSnapshot{
+		Time:    time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC).AddDate(0, 0, day),
+		Printer: printer,
+		Levels: []InkLevel{
+			{Name: "Black", Level: black, Color: "#000000"},
+			{Name: "Cyan", Level: cyan, Color: "#00FFFF"},
+		},
+	}
+}
+
+func TestDataDir(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", "/tmp/xdg-data")
+
+	path, err := DefaultInkHistoryPath()
+	if err != nil {
+		t.Fatalf("DefaultInkHistoryPath() error = %v", err)
+	}
+	if want := filepath.Join("/tmp/xdg-data", "epson-printing", InkHistoryFileName); path != want {
+		t.Errorf("DefaultInkHistoryPath() = %s, want %s", path, want)
+	}
+}
+
+func TestInkHistory_RecordSnapshots(t *testing.T) {
+	history := NewInkHistory(filepath.Join(t.TempDir(), "epson-printing", InkHistoryFileName))
+
+	snapshots, err := history.Snapshots("", time.Time{})
+	if err != nil || len(snapshots) != 0 {
+		t.Fatalf("Snapshots() on missing file = %v, %v; want empty", snapshots, err)
+	}
+
+	const photoURI = "ipp://photo.local/ipp/print"
+	byName := inkSnapshot("photo", 10, 75, 60)
+	byName.URI = photoURI
+	byURI := inkSnapshot(photoURI, 12, 70, 55)
+	byURI.URI = photoURI
+
+	recorded := []InkSnapshot{
+		inkSnapshot("photo", 0, 80, 70),
+		inkSnapshot("office", 1, 50, 50),
+		byName,
+		byURI,
+	}
+	for _, s := range recorded {
+		if err := history.Record(s); err != nil {
+			t.Fatalf("Record() error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		printer string
+		since   time.Time
+		want    []InkSnapshot
+	}{
+		{"all", "", time.Time{}, recorded},
+		{"one printer", "photo", time.Time{}, []InkSnapshot{recorded[0], recorded[2]}},
+		{"printer URI", photoURI, time.Time{}, []InkSnapshot{byName, byURI}},
+		{"since", "", recorded[1].Time, recorded[1:]},
+		{"unknown printer", "lab", time.Time{}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := history.Snapshots(tt.printer, tt.since)
+			if err != nil {
+				t.Fatalf("Snapshots() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Snapshots() =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInkHistory_Record_SetsTime(t *testing.T) {
+	history := NewInkHistory(filepath.Join(t.TempDir(), InkHistoryFileName))
+	if err := history.Record(InkSnapshot{Levels: []InkLevel{{Name: "Black", Level: 50}}}); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+
+	snapshots, err := history.Snapshots("", time.Now().Add(-time.Minute))
+	if err != nil || len(snapshots) != 1 {
+		t.Fatalf("Snapshots() = %v, %v; want the recorded snapshot", snapshots, err)
+	}
+}
+
+func TestInkHistory_Corrupt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), InkHistoryFileName)
+	if err := os.WriteFile(path, []byte("{\"time\":\"2026-03-01T12:00:00Z\",\"levels\":[]}\n\nnot json\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := NewInkHistory(path).Snapshots("", time.Time{}); err == nil {
+		t.Error("expected error for a corrupt line")
+	}
+}
+
+func TestInkUsageOf(t *testing.T) {
+	snapshots := []InkSnapshot{
+		inkSnapshot("photo", 0, 80, 40),
+		inkSnapshot("photo", 5, 70, 30),
+		inkSnapshot("photo", 6, 72, 10), // Black +2 is noise
+		inkSnapshot("photo", 7, 70, 100),
+		inkSnapshot("photo", 10, 65, 90),
+	}
+
+	got := InkUsageOf(snapshots)
+	want := []InkUsage{
+		{Name: "Black", Color: "#000000", First: 80, Level: 65, Used: 15, PerDay: 1.5, DaysLeft: 43.3},
+		{
+			Name: "Cyan", Color: "#00FFFF", First: 40, Level: 90, Used: 40,
+			Refills:  []InkRefill{{Time: snapshots[3].Time, From: 10, To: 100}},
+			PerDay:   4,
+			DaysLeft: 22.5,
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("InkUsageOf() =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestInkUsageOf_EdgeCases(t *testing.T) {
+	if got := InkUsageOf(nil); got != nil {
+		t.Errorf("InkUsageOf(nil) = %v, want nil", got)
+	}
+
+	// A single snapshot spans no time, so there is no rate to forecast from
+	got := InkUsageOf([]InkSnapshot{inkSnapshot("photo", 0, 80, 40)})
+	if len(got) != 2 || got[0].Used != 0 || got[0].DaysLeft >= 0 {
+		t.Errorf("InkUsageOf(single) = %+v, want no usage and no forecast", got)
+	}
+
+	// Tanks missing from older snapshots start where they first appear
+	first := InkSnapshot{Time: inkSnapshot("", 0, 0, 0).Time, Levels: []InkLevel{{Name: "Black", Level: 90}}}
+	got = InkUsageOf([]InkSnapshot{first, inkSnapshot("", 2, 80, 60)})
+	if got[1].First != 60 || got[1].Used != 0 || got[0].Used != 10 || got[0].PerDay != 5 {
+		t.Errorf("InkUsageOf() = %+v", got)
+	}
+	// Unknown levels (-1, -2, -3) are neither refills nor ink used
+	snapshots := []InkSnapshot{
+		inkSnapshot("", 0, -1, 50),
+		inkSnapshot("", 1, 80, -3),
+		inkSnapshot("", 2, -2, 40),
+		inkSnapshot("", 3, 70, 30),
+		inkSnapshot("", 4, 60, -1),
+	}
+	got = InkUsageOf(snapshots)
+	want := []InkUsage{
+		{Name: "Black", Color: "#000000", First: 80, Level: 60, Used: 20, PerDay: 5, DaysLeft: 12},
+		{Name: "Cyan", Color: "#00FFFF", First: 50, Level: 30, Used: 20, PerDay: 5, DaysLeft: 6},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("InkUsageOf(unknown levels) =\n%+v\nwant\n%+v", got, want)
+	}
+
+	// A tank that never reported a level has no usage
+	got = InkUsageOf([]InkSnapshot{inkSnapshot("", 0, -1, 50), inkSnapshot("", 1, -1, 40)})
+	if got[0].Level >= 0 || got[0].Used != 0 || len(got[0].Refills) != 0 || got[0].DaysLeft >= 0 {
+		t.Errorf("InkUsageOf(never known) = %+v", got[0])
+	}
+}
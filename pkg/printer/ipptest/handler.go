@@ -0,0 +1,374 @@
+package ipptest
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/OpenPrinting/goipp"
+)
+
+// ServeHTTP decodes an IPP request, followed by the document for job
+// submissions, and writes the printer's response
+func (p *Printer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "IPP requests must use POST", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req goipp.Message
+	if err := req.Decode(r.Body); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	// The document follows the IPP header in the request body
+	document, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resp, httpStatus, done := p.handle(&req, document)
+	if httpStatus != 0 {
+		http.Error(w, http.StatusText(httpStatus), httpStatus)
+		return
+	}
+	if done != nil && p.OnJob != nil {
+		p.OnJob(*done)
+	}
+
+	w.Header().Set("Content-Type", goipp.ContentType)
+	_ = resp.Encode(w)
+}
+
+// handle builds the response to a request under the printer lock. It
+// returns an HTTP status instead when an injected fault asks for one, and
+// the finished job when the request completed a job submission.
+func (p *Printer) handle(req *goipp.Message, document []byte) (resp *goipp.Message, httpStatus int, done *Job) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.requests = append(p.requests, req)
+	op := goipp.Op(req.Code)
+
+	if f, ok := p.takeFault(op); ok {
+		if f.HTTPStatus != 0 {
+			return nil, f.HTTPStatus, nil
+		}
+		return p.response(req, f.Status), 0, nil
+	}
+
+	switch op {
+	case goipp.OpGetPrinterAttributes:
+		return p.getPrinterAttributes(req), 0, nil
+	case goipp.OpPrintJob:
+		return p.createJob(req, document, true)
+	case goipp.OpCreateJob:
+		return p.createJob(req, nil, false)
+	case goipp.OpSendDocument:
+		return p.sendDocument(req, document)
+	case goipp.OpGetJobAttributes:
+		return p.getJobAttributes(req), 0, nil
+	case goipp.OpGetJobs:
+		return p.getJobs(req), 0, nil
+	case goipp.OpCancelJob:
+		return p.setJobState(req, JobCanceled), 0, nil
+	case goipp.OpHoldJob:
+		return p.setJobState(req, JobHeld), 0, nil
+	case goipp.OpReleaseJob:
+		return p.setJobState(req, JobPending), 0, nil
+	default:
+		return p.response(req, goipp.StatusErrorOperationNotSupported), 0, nil
+	}
+}
+
+// takeFault returns the first injected fault matching op and uses it up
+func (p *Printer) takeFault(op goipp.Op) (Fault, bool) {
+	for i, f := range p.faults {
+		if f.Op != 0 && f.Op != op {
+			continue
+		}
+		if f.Count > 0 {
+			p.faults[i].Count--
+			if p.faults[i].Count == 0 {
+				p.faults = slices.Delete(p.faults, i, i+1)
+			}
+		}
+		return f, true
+	}
+	return Fault{}, false
+}
+
+// response returns an empty response with the standard operation attributes
+func (p *Printer) response(req *goipp.Message, status goipp.Status) *goipp.Message {
+	resp := goipp.NewResponse(goipp.DefaultVersion, status, req.RequestID)
+	resp.Operation.Add(goipp.MakeAttr("attributes-charset",
+		goipp.TagCharset, goipp.String("utf-8")))
+	resp.Operation.Add(goipp.MakeAttr("attributes-natural-language",
+		goipp.TagLanguage, goipp.String("en-US")))
+	if status != goipp.StatusOk {
+		resp.Operation.Add(goipp.MakeAttr("status-message",
+			goipp.TagText, goipp.String(status.String())))
+	}
+	return resp
+}
+
+// getPrinterAttributes returns the requested printer attributes
+func (p *Printer) getPrinterAttributes(req *goipp.Message) *goipp.Message {
+	resp := p.response(req, goipp.StatusOk)
+
+	requested := stringValues(req.Operation, "requested-attributes")
+	all := len(requested) == 0 || slices.Contains(requested, "all")
+	for _, attr := range p.printerAttributes() {
+		if all || slices.Contains(requested, attr.Name) {
+			resp.Printer.Add(attr)
+		}
+	}
+	return resp
+}
+
+// createJob adds a job for Print-Job (with its document) or Create-Job
+func (p *Printer) createJob(req *goipp.Message, document []byte, complete bool) (*goipp.Message, int, *Job) {
+	format := stringValue(req.Operation, "document-format")
+	if complete && !p.supportsFormat(format) {
+		return p.response(req, goipp.StatusErrorDocumentFormatNotSupported), 0, nil
+	}
+
+	p.nextJobID++
+	job := &Job{
+		ID:         p.nextJobID,
+		Name:       stringValue(req.Operation, "job-name"),
+		User:       userName(req),
+		Format:     format,
+		State:      p.initialJobState(),
+		Attributes: slices.Clone(req.Job),
+	}
+	if complete {
+		job.Documents = [][]byte{document}
+	}
+	p.jobs = append(p.jobs, job)
+
+	resp := p.response(req, goipp.StatusOk)
+	if unsupported := p.unsupportedAttributes(req.Job); len(unsupported) > 0 {
+		resp.Code = goipp.Code(goipp.StatusOkIgnoredOrSubstituted)
+		resp.Operation.Add(goipp.MakeAttr("status-message",
+			goipp.TagText, goipp.String(goipp.StatusOkIgnoredOrSubstituted.String())))
+		resp.Unsupported = unsupported
+	}
+	resp.Job = p.jobAttributes(job)
+
+	if complete {
+		done := job.clone()
+		return resp, 0, &done
+	}
+	return resp, 0, nil
+}
+
+// sendDocument adds a document to a job created with Create-Job
+func (p *Printer) sendDocument(req *goipp.Message, document []byte) (*goipp.Message, int, *Job) {
+	job := p.findJob(intValue(req.Operation, "job-id"))
+	if job == nil {
+		return p.response(req, goipp.StatusErrorNotFound), 0, nil
+	}
+	if terminal(job.State) {
+		return p.response(req, goipp.StatusErrorNotPossible), 0, nil
+	}
+
+	format := stringValue(req.Operation, "document-format")
+	if !p.supportsFormat(format) {
+		return p.response(req, goipp.StatusErrorDocumentFormatNotSupported), 0, nil
+	}
+	if format != "" {
+		job.Format = format
+	}
+	job.Documents = append(job.Documents, bytes.Clone(document))
+
+	resp := p.response(req, goipp.StatusOk)
+	resp.Job = p.jobAttributes(job)
+
+	var done *Job
+	if last := findAttr(req.Operation, "last-document"); last != nil && len(last.Values) > 0 {
+		if b, ok := last.Values[0].V.(goipp.Boolean); ok && bool(b) {
+			finished := job.clone()
+			done = &finished
+		}
+	}
+	return resp, 0, done
+}
+
+// getJobAttributes returns the status of a job and advances its state
+func (p *Printer) getJobAttributes(req *goipp.Message) *goipp.Message {
+	job := p.findJob(intValue(req.Operation, "job-id"))
+	if job == nil {
+		return p.response(req, goipp.StatusErrorNotFound)
+	}
+
+	resp := p.response(req, goipp.StatusOk)
+	resp.Job = p.jobAttributes(job)
+	p.advance(job)
+	return resp
+}
+
+// getJobs lists jobs selected by which-jobs, my-jobs and limit, each in its
+// own job attributes group
+func (p *Printer) getJobs(req *goipp.Message) *goipp.Message {
+	which := stringValue(req.Operation, "which-jobs")
+	myJobs := false
+	if attr := findAttr(req.Operation, "my-jobs"); attr != nil && len(attr.Values) > 0 {
+		b, _ := attr.Values[0].V.(goipp.Boolean)
+		myJobs = bool(b)
+	}
+	limit := intValue(req.Operation, "limit")
+	user := userName(req)
+
+	// Groups replaces the named groups when encoding, so it must include
+	// the operation attributes
+	resp := p.response(req, goipp.StatusOk)
+	resp.Groups = goipp.Groups{{Tag: goipp.TagOperationGroup, Attrs: resp.Operation}}
+	count := 0
+	for _, job := range p.jobs {
+		switch {
+		case which == "completed" && !terminal(job.State),
+			(which == "" || which == "not-completed") && terminal(job.State),
+			myJobs && job.User != user:
+			continue
+		}
+		if limit > 0 && count == limit {
+			break
+		}
+		count++
+
+		resp.Groups = append(resp.Groups, goipp.Group{Tag: goipp.TagJobGroup, Attrs: p.jobAttributes(job)})
+		p.advance(job)
+	}
+	return resp
+}
+
+// setJobState handles Cancel-Job, Hold-Job and Release-Job
+func (p *Printer) setJobState(req *goipp.Message, state int) *goipp.Message {
+	job := p.findJob(intValue(req.Operation, "job-id"))
+	if job == nil {
+		return p.response(req, goipp.StatusErrorNotFound)
+	}
+	if terminal(job.State) || (state == JobPending && job.State != JobHeld) {
+		return p.response(req, goipp.StatusErrorNotPossible)
+	}
+
+	job.State = state
+	return p.response(req, goipp.StatusOk)
+}
+
+// jobAttributes returns the status attributes of a job
+func (p *Printer) jobAttributes(job *Job) goipp.Attributes {
+	var attrs goipp.Attributes
+	attrs.Add(goipp.MakeAttr("job-id", goipp.TagInteger, goipp.Integer(job.ID)))
+	attrs.Add(goipp.MakeAttr("job-uri", goipp.TagURI, goipp.String(p.jobURI(job))))
+	attrs.Add(goipp.MakeAttr("job-state", goipp.TagEnum, goipp.Integer(job.State)))
+	attrs.Add(goipp.MakeAttr("job-state-reasons", goipp.TagKeyword, goipp.String(jobStateReason(job.State))))
+	if job.Name != "" {
+		attrs.Add(goipp.MakeAttr("job-name", goipp.TagName, goipp.String(job.Name)))
+	}
+	if job.User != "" {
+		attrs.Add(goipp.MakeAttr("job-originating-user-name", goipp.TagName, goipp.String(job.User)))
+	}
+	return attrs
+}
+
+// jobURI returns the URI of a job
+func (p *Printer) jobURI(job *Job) string {
+	return "ipp://localhost/ipp/print/" + strconv.Itoa(job.ID)
+}
+
+// jobStateReason returns a job-state-reasons keyword matching the state
+func jobStateReason(state int) string {
+	switch state {
+	case JobHeld:
+		return "job-hold-until-specified"
+	case JobProcessing:
+		return "job-printing"
+	case JobCanceled:
+		return "job-canceled-by-user"
+	case JobAborted:
+		return "aborted-by-system"
+	case JobCompleted:
+		return "job-completed-successfully"
+	default:
+		return "none"
+	}
+}
+
+// initialJobState returns the first state of JobProgression
+func (p *Printer) initialJobState() int {
+	if len(p.JobProgression) == 0 {
+		return JobCompleted
+	}
+	return p.JobProgression[0]
+}
+
+// advance moves a job to the next state of JobProgression, unless it is
+// held, finished, or was moved out of the progression by SetJobState
+func (p *Printer) advance(job *Job) {
+	i := slices.Index(p.JobProgression, job.State)
+	if i >= 0 && i+1 < len(p.JobProgression) && job.State != JobHeld {
+		job.State = p.JobProgression[i+1]
+	}
+}
+
+// supportsFormat reports whether documents of the format are accepted.
+// A missing format or application/octet-stream lets the printer detect it.
+func (p *Printer) supportsFormat(format string) bool {
+	return format == "" || format == "application/octet-stream" ||
+		len(p.DocumentFormats) == 0 || slices.Contains(p.DocumentFormats, format)
+}
+
+// unsupportedAttributes returns the job settings the printer does not
+// support, as reported in the unsupported attributes group
+func (p *Printer) unsupportedAttributes(job goipp.Attributes) goipp.Attributes {
+	var unsupported goipp.Attributes
+
+	if attr := findAttr(job, "media-col"); attr != nil && len(attr.Values) > 0 {
+		if col, ok := attr.Values[0].V.(goipp.Collection); ok {
+			var rejected goipp.Collection
+			for _, member := range col {
+				var supported []string
+				switch member.Name {
+				case "media-size-name":
+					supported = p.Media
+				case "media-source":
+					supported = p.MediaSources
+				case "media-type":
+					supported = p.MediaTypes
+				default:
+					continue
+				}
+				if len(supported) > 0 && len(member.Values) > 0 &&
+					!containsFold(supported, member.Values[0].V.String()) {
+					rejected.Add(member)
+				}
+			}
+			if len(rejected) > 0 {
+				unsupported.Add(goipp.MakeAttribute("media-col", goipp.TagBeginCollection, rejected))
+			}
+		}
+	}
+
+	if attr := findAttr(job, "print-quality"); attr != nil && len(attr.Values) > 0 && len(p.PrintQualities) > 0 {
+		if quality, ok := attr.Values[0].V.(goipp.Integer); ok && !slices.Contains(p.PrintQualities, int(quality)) {
+			unsupported.Add(*attr)
+		}
+	}
+
+	return unsupported
+}
+
+// findAttr returns the named attribute, or nil
+func findAttr(attrs goipp.Attributes, name string) *goipp.Attribute {
+	for i := range attrs {
+		if attrs[i].Name == name {
+			return &attrs[i]
+		}
+	}
+	return nil
+}
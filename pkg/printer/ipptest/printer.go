@@ -0,0 +1,365 @@
+// Package ipptest provides an in-process IPP printer for tests.
+//
+// A Printer answers Get-Printer-Attributes with configurable markers, states
+// and supported values, accepts jobs via Print-Job or Create-Job and
+// Send-Document, and records every request, job and document so tests can
+// assert on what a client sent. Jobs advance through configurable states as
+// they are queried, and errors can be injected per operation.
+//
+//	p := ipptest.NewPrinter()
+//	server := ipptest.NewServer(p)
+//	defer server.Close()
+//
+//	client := printer.NewClient(server.URI)
+package ipptest
+
+import (
+	"cmp"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/OpenPrinting/goipp"
+)
+
+// Printer states as defined by RFC 8011, 5.4.11
+const (
+	PrinterIdle       = 3
+	PrinterProcessing = 4
+	PrinterStopped    = 5
+)
+
+// Job states as defined by RFC 8011, 5.3.7
+const (
+	JobPending    = 3
+	JobHeld       = 4
+	JobProcessing = 5
+	JobStopped    = 6
+	JobCanceled   = 7
+	JobAborted    = 8
+	JobCompleted  = 9
+)
+
+// Marker is an ink supply reported in the marker-* printer attributes
+type Marker struct {
+	Name      string `json:"name"`       // e.g. "Cyan"
+	Color     string `json:"color"`      // e.g. "#00FFFF"
+	Type      string `json:"type"`       // e.g. "ink-bottle" (default "ink-cartridge")
+	Level     int    `json:"level"`      // Percent, or -1/-2/-3 for unknown values
+	LowLevel  int    `json:"low_level"`  // Level at which the printer reports it as low
+	HighLevel int    `json:"high_level"` // Level of a full supply
+}
+
+// Job is a job received by the printer
+type Job struct {
+	ID         int
+	Name       string
+	User       string
+	Format     string           // document-format of the request
+	State      int              // Current job state
+	Attributes goipp.Attributes // Job attributes sent by the client
+	Documents  [][]byte         // Documents in the order received
+}
+
+// Fault makes the printer fail requests, for error injection
+type Fault struct {
+	Op         goipp.Op     // Operation to fail; 0 fails any operation
+	Status     goipp.Status // IPP status to return
+	HTTPStatus int          // HTTP status to return instead of an IPP response, if set
+	Count      int          // Number of requests to fail; 0 fails all of them
+}
+
+// Printer is an emulated IPP printer. It is an http.Handler and can be
+// served with NewServer or any HTTP server. Change its settings while it is
+// serving requests only through Update.
+type Printer struct {
+	Info            string
+	MakeAndModel    string
+	State           int
+	StateReasons    []string
+	StateMessage    string
+	Markers         []Marker
+	Media           []string // media-supported
+	MediaSources    []string // media-source-supported
+	MediaTypes      []string // media-type-supported
+	PrintQualities  []int    // print-quality-supported
+	DocumentFormats []string // document-format-supported
+
+	// Extra printer attributes returned after the ones above
+	Extra goipp.Attributes
+
+	// JobProgression lists the states a new job passes through, advancing
+	// one step each time it is queried with Get-Job-Attributes or Get-Jobs
+	JobProgression []int
+
+	// OnJob is called with a copy of each job once its last document has
+	// been received, without holding the printer lock
+	OnJob func(job Job)
+
+	mu        sync.Mutex
+	jobs      []*Job
+	requests  []*goipp.Message
+	faults    []Fault
+	nextJobID int
+}
+
+// NewPrinter returns an idle Epson ET-8550 with six full ink tanks
+func NewPrinter() *Printer {
+	return &Printer{
+		Info:         "EPSON ET-8550 Series",
+		MakeAndModel: "EPSON ET-8550 Series",
+		State:        PrinterIdle,
+		StateReasons: []string{"none"},
+		Markers: []Marker{
+			newMarker("Black", "#000000"),
+			newMarker("Photo Black", "#000000"),
+			newMarker("Cyan", "#00FFFF"),
+			newMarker("Magenta", "#FF00FF"),
+			newMarker("Yellow", "#FFFF00"),
+			newMarker("Gray", "#808080"),
+		},
+		Media: []string{
+			"na_index-4x6_4x6in", "na_5x7_5x7in", "na_govt-letter_8x10in",
+			"na_letter_8.5x11in", "na_legal_8.5x14in", "iso_a4_210x297mm",
+			"iso_a3_297x420mm", "na_super-b_13x19in",
+		},
+		MediaSources: []string{"auto", "main", "rear", "photo"},
+		MediaTypes: []string{
+			"stationery", "stationery-coated", "photographic-glossy",
+			"photographic-semi-gloss", "photographic-matte",
+		},
+		PrintQualities:  []int{3, 4, 5},
+		DocumentFormats: []string{"application/pdf", "image/jpeg", "image/png", "image/pwg-raster"},
+		JobProgression:  []int{JobPending, JobProcessing, JobCompleted},
+	}
+}
+
+// newMarker returns a full ink tank
+func newMarker(name, color string) Marker {
+	return Marker{Name: name, Color: color, Type: "ink-bottle", Level: 100, LowLevel: 15, HighLevel: 100}
+}
+
+// Update changes the printer settings while it may be serving requests
+func (p *Printer) Update(change func(p *Printer)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	change(p)
+}
+
+// InjectFault makes the printer fail matching requests until the fault's
+// count is used up. Faults are matched in the order they were injected.
+func (p *Printer) InjectFault(f Fault) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if f.Count == 0 {
+		f.Count = -1 // Unlimited
+	}
+	p.faults = append(p.faults, f)
+}
+
+// ClearFaults removes all injected faults
+func (p *Printer) ClearFaults() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.faults = nil
+}
+
+// Jobs returns copies of all jobs received so far, oldest first
+func (p *Printer) Jobs() []Job {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	jobs := make([]Job, 0, len(p.jobs))
+	for _, job := range p.jobs {
+		jobs = append(jobs, job.clone())
+	}
+	return jobs
+}
+
+// Job returns a copy of the job with the given ID
+func (p *Printer) Job(id int) (Job, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if job := p.findJob(id); job != nil {
+		return job.clone(), true
+	}
+	return Job{}, false
+}
+
+// SetJobState moves a job to the given state
+func (p *Printer) SetJobState(id, state int) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	job := p.findJob(id)
+	if job == nil {
+		return false
+	}
+	job.State = state
+	return true
+}
+
+// Requests returns all decoded requests received so far, without documents
+func (p *Printer) Requests() []*goipp.Message {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return slices.Clone(p.requests)
+}
+
+// PrinterAttributes returns all printer attributes as sent in response to
+// Get-Printer-Attributes with requested-attributes "all"
+func (p *Printer) PrinterAttributes() goipp.Attributes {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.printerAttributes()
+}
+
+// clone returns a deep copy of the job
+func (j *Job) clone() Job {
+	c := *j
+	c.Attributes = slices.Clone(j.Attributes)
+	c.Documents = make([][]byte, len(j.Documents))
+	for i, doc := range j.Documents {
+		c.Documents[i] = slices.Clone(doc)
+	}
+	return c
+}
+
+// Server is a Printer served over HTTP on a local port
+type Server struct {
+	*httptest.Server
+	URI string // Printer URI to pass to clients, e.g. http://127.0.0.1:1234/ipp/print
+}
+
+// NewServer starts serving the printer. The caller must Close the server.
+func NewServer(p *Printer) *Server {
+	server := httptest.NewServer(p)
+	return &Server{Server: server, URI: server.URL + "/ipp/print"}
+}
+
+// printerAttributes builds the printer attributes; p.mu must be held
+func (p *Printer) printerAttributes() goipp.Attributes {
+	var attrs goipp.Attributes
+	addText := func(name string, tag goipp.Tag, values ...string) {
+		if len(values) == 0 {
+			return
+		}
+		attr := goipp.Attribute{Name: name}
+		for _, v := range values {
+			attr.Values.Add(tag, goipp.String(v))
+		}
+		attrs.Add(attr)
+	}
+	addInts := func(name string, tag goipp.Tag, values ...int) {
+		if len(values) == 0 {
+			return
+		}
+		attr := goipp.Attribute{Name: name}
+		for _, v := range values {
+			attr.Values.Add(tag, goipp.Integer(v))
+		}
+		attrs.Add(attr)
+	}
+
+	addText("printer-info", goipp.TagText, p.Info)
+	addText("printer-make-and-model", goipp.TagText, p.MakeAndModel)
+	addInts("printer-state", goipp.TagEnum, p.State)
+	addText("printer-state-reasons", goipp.TagKeyword, p.StateReasons...)
+	if p.StateMessage != "" {
+		addText("printer-state-message", goipp.TagText, p.StateMessage)
+	}
+
+	if len(p.Markers) > 0 {
+		var names, colors, types []string
+		var levels, low, high []int
+		for _, m := range p.Markers {
+			names = append(names, m.Name)
+			colors = append(colors, m.Color)
+			types = append(types, cmp.Or(m.Type, "ink-cartridge"))
+			levels = append(levels, m.Level)
+			low = append(low, m.LowLevel)
+			high = append(high, m.HighLevel)
+		}
+		addText("marker-names", goipp.TagName, names...)
+		addText("marker-colors", goipp.TagName, colors...)
+		addText("marker-types", goipp.TagKeyword, types...)
+		addInts("marker-levels", goipp.TagInteger, levels...)
+		addInts("marker-low-levels", goipp.TagInteger, low...)
+		addInts("marker-high-levels", goipp.TagInteger, high...)
+	}
+
+	addText("media-supported", goipp.TagKeyword, p.Media...)
+	addText("media-source-supported", goipp.TagKeyword, p.MediaSources...)
+	addText("media-type-supported", goipp.TagKeyword, p.MediaTypes...)
+	addInts("print-quality-supported", goipp.TagEnum, p.PrintQualities...)
+	addText("document-format-supported", goipp.TagMimeType, p.DocumentFormats...)
+
+	return append(attrs, p.Extra...)
+}
+
+// findJob returns the job with the given ID; p.mu must be held
+func (p *Printer) findJob(id int) *Job {
+	for _, job := range p.jobs {
+		if job.ID == id {
+			return job
+		}
+	}
+	return nil
+}
+
+// terminal reports whether a job state is final
+func terminal(state int) bool {
+	return state == JobCanceled || state == JobAborted || state == JobCompleted
+}
+
+// userName returns the requesting-user-name of a request
+func userName(req *goipp.Message) string {
+	return stringValue(req.Operation, "requesting-user-name")
+}
+
+// stringValue returns the first value of an attribute as a string, or ""
+func stringValue(attrs goipp.Attributes, name string) string {
+	for _, attr := range attrs {
+		if attr.Name == name && len(attr.Values) > 0 {
+			return attr.Values[0].V.String()
+		}
+	}
+	return ""
+}
+
+// intValue returns the first value of an integer attribute, or 0
+func intValue(attrs goipp.Attributes, name string) int {
+	for _, attr := range attrs {
+		if attr.Name == name && len(attr.Values) > 0 {
+			if v, ok := attr.Values[0].V.(goipp.Integer); ok {
+				return int(v)
+			}
+		}
+	}
+	return 0
+}
+
+// stringValues returns all values of an attribute as strings
+func stringValues(attrs goipp.Attributes, name string) []string {
+	var values []string
+	for _, attr := range attrs {
+		if attr.Name == name {
+			for _, v := range attr.Values {
+				values = append(values, v.V.String())
+			}
+		}
+	}
+	return values
+}
+
+// containsFold reports whether values contains s, ignoring case
+func containsFold(values []string, s string) bool {
+	return slices.ContainsFunc(values, func(v string) bool {
+		return strings.EqualFold(v, s)
+	})
+}
+
+var _ http.Handler = (*Printer)(nil)
@@ -0,0 +1,270 @@
+package ipptest
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+
+	"github.com/OpenPrinting/goipp"
+)
+
+// Test helper: send a request followed by a document and decode the response
+func roundTrip(t *testing.T, server *Server, req *goipp.Message, document []byte) *goipp.Message {
+	t.Helper()
+
+	body, err := req.EncodeBytes()
+	if err != nil {
+		t.Fatalf("encoding request: %v", err)
+	}
+	httpResp, err := http.Post(server.URI, goipp.ContentType, bytes.NewReader(append(body, document...)))
+	if err != nil {
+		t.Fatalf("sending request: %v", err)
+	}
+	defer func() {
+		_ = httpResp.Body.Close()
+	}()
+	if httpResp.StatusCode != http.StatusOK {
+		t.Fatalf("HTTP status = %d", httpResp.StatusCode)
+	}
+
+	var resp goipp.Message
+	if err := resp.Decode(httpResp.Body); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	return &resp
+}
+
+// Test helper: build a request with the standard operation attributes
+func newRequest(op goipp.Op, attrs ...goipp.Attribute) *goipp.Message {
+	req := goipp.NewRequest(goipp.DefaultVersion, op, 1)
+	req.Operation.Add(goipp.MakeAttr("attributes-charset", goipp.TagCharset, goipp.String("utf-8")))
+	req.Operation.Add(goipp.MakeAttr("attributes-natural-language", goipp.TagLanguage, goipp.String("en-US")))
+	req.Operation.Add(goipp.MakeAttr("requesting-user-name", goipp.TagName, goipp.String("alice")))
+	for _, attr := range attrs {
+		req.Operation.Add(attr)
+	}
+	return req
+}
+
+func TestPrinter_GetPrinterAttributes(t *testing.T) {
+	p := NewPrinter()
+	p.Markers = []Marker{{Name: "Cyan", Color: "#00FFFF", Level: 42}}
+	p.State = PrinterStopped
+	server := NewServer(p)
+	defer server.Close()
+
+	tests := []struct {
+		name      string
+		requested []string
+		want      []string
+		notWant   []string
+	}{
+		{"all", nil, []string{"printer-state", "marker-levels", "media-supported"}, nil},
+		{"requested only", []string{"marker-levels"}, []string{"marker-levels"}, []string{"printer-state"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := newRequest(goipp.OpGetPrinterAttributes)
+			for _, name := range tt.requested {
+				req.Operation.Add(goipp.MakeAttr("requested-attributes", goipp.TagKeyword, goipp.String(name)))
+			}
+
+			resp := roundTrip(t, server, req, nil)
+			for _, name := range tt.want {
+				if findAttr(resp.Printer, name) == nil {
+					t.Errorf("missing printer attribute %s", name)
+				}
+			}
+			for _, name := range tt.notWant {
+				if findAttr(resp.Printer, name) != nil {
+					t.Errorf("unexpected printer attribute %s", name)
+				}
+			}
+		})
+	}
+
+	resp := roundTrip(t, server, newRequest(goipp.OpGetPrinterAttributes), nil)
+	if got := intValue(resp.Printer, "printer-state"); got != PrinterStopped {
+		t.Errorf("printer-state = %d, want %d", got, PrinterStopped)
+	}
+	if got := intValue(resp.Printer, "marker-levels"); got != 42 {
+		t.Errorf("marker-levels = %d, want 42", got)
+	}
+}
+
+func TestPrinter_PrintJob(t *testing.T) {
+	p := NewPrinter()
+	var finished []Job
+	p.OnJob = func(job Job) { finished = append(finished, job) }
+	server := NewServer(p)
+	defer server.Close()
+
+	req := newRequest(goipp.OpPrintJob,
+		goipp.MakeAttr("job-name", goipp.TagName, goipp.String("photo.pdf")),
+		goipp.MakeAttr("document-format", goipp.TagMimeType, goipp.String("application/pdf")))
+	req.Job.Add(goipp.MakeAttr("copies", goipp.TagInteger, goipp.Integer(2)))
+
+	resp := roundTrip(t, server, req, []byte("%PDF-1.7"))
+	if goipp.Status(resp.Code) != goipp.StatusOk {
+		t.Fatalf("status = %s", goipp.Status(resp.Code))
+	}
+	id := intValue(resp.Job, "job-id")
+
+	job, ok := p.Job(id)
+	if !ok {
+		t.Fatalf("job %d not recorded", id)
+	}
+	if job.Name != "photo.pdf" || job.User != "alice" || job.Format != "application/pdf" {
+		t.Errorf("job = %+v", job)
+	}
+	if len(job.Documents) != 1 || string(job.Documents[0]) != "%PDF-1.7" {
+		t.Errorf("documents = %q", job.Documents)
+	}
+	if intValue(job.Attributes, "copies") != 2 {
+		t.Errorf("copies not recorded: %v", job.Attributes)
+	}
+	if len(finished) != 1 || finished[0].ID != id {
+		t.Errorf("OnJob called with %v, want job %d", finished, id)
+	}
+
+	// The job advances one state per query
+	query := newRequest(goipp.OpGetJobAttributes, goipp.MakeAttr("job-id", goipp.TagInteger, goipp.Integer(id)))
+	for _, want := range []int{JobPending, JobProcessing, JobCompleted, JobCompleted} {
+		resp := roundTrip(t, server, query, nil)
+		if got := intValue(resp.Job, "job-state"); got != want {
+			t.Errorf("job-state = %d, want %d", got, want)
+		}
+	}
+}
+
+func TestPrinter_CreateJobSendDocument(t *testing.T) {
+	p := NewPrinter()
+	server := NewServer(p)
+	defer server.Close()
+
+	resp := roundTrip(t, server, newRequest(goipp.OpCreateJob,
+		goipp.MakeAttr("job-name", goipp.TagName, goipp.String("album"))), nil)
+	id := intValue(resp.Job, "job-id")
+
+	for i, doc := range []string{"first", "second"} {
+		req := newRequest(goipp.OpSendDocument,
+			goipp.MakeAttr("job-id", goipp.TagInteger, goipp.Integer(id)),
+			goipp.MakeAttr("document-format", goipp.TagMimeType, goipp.String("image/jpeg")),
+			goipp.MakeAttr("last-document", goipp.TagBoolean, goipp.Boolean(i == 1)))
+		if resp := roundTrip(t, server, req, []byte(doc)); goipp.Status(resp.Code) != goipp.StatusOk {
+			t.Fatalf("Send-Document status = %s", goipp.Status(resp.Code))
+		}
+	}
+
+	job, _ := p.Job(id)
+	if len(job.Documents) != 2 || string(job.Documents[1]) != "second" {
+		t.Errorf("documents = %q", job.Documents)
+	}
+	if job.Format != "image/jpeg" {
+		t.Errorf("format = %q, want image/jpeg", job.Format)
+	}
+}
+
+func TestPrinter_Substituted(t *testing.T) {
+	server := NewServer(NewPrinter())
+	defer server.Close()
+
+	var col goipp.Collection
+	col.Add(goipp.MakeAttr("media-size-name", goipp.TagKeyword, goipp.String("iso_a4_210x297mm")))
+	col.Add(goipp.MakeAttr("media-type", goipp.TagKeyword, goipp.String("transparency")))
+	req := newRequest(goipp.OpPrintJob)
+	req.Job.Add(goipp.MakeAttribute("media-col", goipp.TagBeginCollection, col))
+	req.Job.Add(goipp.MakeAttr("print-quality", goipp.TagEnum, goipp.Integer(6)))
+
+	resp := roundTrip(t, server, req, []byte("%PDF"))
+	if goipp.Status(resp.Code) != goipp.StatusOkIgnoredOrSubstituted {
+		t.Fatalf("status = %s, want %s", goipp.Status(resp.Code), goipp.StatusOkIgnoredOrSubstituted)
+	}
+	mediaCol := findAttr(resp.Unsupported, "media-col")
+	if mediaCol == nil {
+		t.Fatal("media-col not reported as unsupported")
+	}
+	if rejected := mediaCol.Values[0].V.(goipp.Collection); len(rejected) != 1 || rejected[0].Name != "media-type" {
+		t.Errorf("rejected media-col members = %v, want media-type", rejected)
+	}
+	if findAttr(resp.Unsupported, "print-quality") == nil {
+		t.Error("print-quality not reported as unsupported")
+	}
+}
+
+func TestPrinter_Faults(t *testing.T) {
+	p := NewPrinter()
+	p.InjectFault(Fault{Op: goipp.OpPrintJob, Status: goipp.StatusErrorBusy, Count: 2})
+	server := NewServer(p)
+	defer server.Close()
+
+	statuses := []goipp.Status{goipp.StatusErrorBusy, goipp.StatusErrorBusy, goipp.StatusOk}
+	for i, want := range statuses {
+		// Other operations are not affected
+		if resp := roundTrip(t, server, newRequest(goipp.OpGetPrinterAttributes), nil); goipp.Status(resp.Code) != goipp.StatusOk {
+			t.Errorf("Get-Printer-Attributes status = %s", goipp.Status(resp.Code))
+		}
+
+		resp := roundTrip(t, server, newRequest(goipp.OpPrintJob), []byte("%PDF"))
+		if got := goipp.Status(resp.Code); got != want {
+			t.Errorf("attempt %d: status = %s, want %s", i+1, got, want)
+		}
+	}
+	if got := len(p.Jobs()); got != 1 {
+		t.Errorf("jobs = %d, want 1", got)
+	}
+
+	p.InjectFault(Fault{HTTPStatus: http.StatusUnauthorized})
+	body, _ := newRequest(goipp.OpGetPrinterAttributes).EncodeBytes()
+	httpResp, err := http.Post(server.URI, goipp.ContentType, bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = httpResp.Body.Close()
+	if httpResp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("HTTP status = %d, want 401", httpResp.StatusCode)
+	}
+}
+
+func TestPrinter_JobControl(t *testing.T) {
+	p := NewPrinter()
+	server := NewServer(p)
+	defer server.Close()
+
+	resp := roundTrip(t, server, newRequest(goipp.OpPrintJob), []byte("%PDF"))
+	jobID := goipp.MakeAttr("job-id", goipp.TagInteger, goipp.Integer(intValue(resp.Job, "job-id")))
+
+	tests := []struct {
+		op        goipp.Op
+		want      goipp.Status
+		wantState int
+	}{
+		{goipp.OpHoldJob, goipp.StatusOk, JobHeld},
+		{goipp.OpReleaseJob, goipp.StatusOk, JobPending},
+		{goipp.OpReleaseJob, goipp.StatusErrorNotPossible, JobPending},
+		{goipp.OpCancelJob, goipp.StatusOk, JobCanceled},
+		{goipp.OpCancelJob, goipp.StatusErrorNotPossible, JobCanceled},
+	}
+
+	for _, tt := range tests {
+		resp := roundTrip(t, server, newRequest(tt.op, jobID), nil)
+		if got := goipp.Status(resp.Code); got != tt.want {
+			t.Errorf("%s: status = %s, want %s", tt.op, got, tt.want)
+		}
+		if job := p.Jobs()[0]; job.State != tt.wantState {
+			t.Errorf("%s: state = %d, want %d", tt.op, job.State, tt.wantState)
+		}
+	}
+
+	// Canceled jobs are only listed as completed
+	list := roundTrip(t, server, newRequest(goipp.OpGetJobs), nil)
+	if jobs := len(list.AttrGroups()) - 1; jobs != 0 {
+		t.Errorf("not-completed jobs = %d, want 0", jobs)
+	}
+	list = roundTrip(t, server, newRequest(goipp.OpGetJobs,
+		goipp.MakeAttr("which-jobs", goipp.TagKeyword, goipp.String("completed"))), nil)
+	if jobs := len(list.AttrGroups()) - 1; jobs != 1 {
+		t.Errorf("completed jobs = %d, want 1", jobs)
+	}
+}
@@ -0,0 +1,296 @@
+package printer
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/OpenPrinting/goipp"
+)
+
+// JobState is the IPP job-state of a print job
+type JobState int
+
+// Job states as defined by RFC 8011, 5.3.7
+const (
+	JobPending    JobState = 3
+	JobHeld       JobState = 4
+	JobProcessing JobState = 5
+	JobStopped    JobState = 6
+	JobCanceled   JobState = 7
+	JobAborted    JobState = 8
+	JobCompleted  JobState = 9
+)
+
+// Values for the which-jobs attribute of GetJobs
+const (
+	WhichJobsNotCompleted = "not-completed"
+	WhichJobsCompleted    = "completed"
+	WhichJobsAll          = "all"
+)
+
+// DefaultPollInterval is the interval between job status queries in WaitJob
+const DefaultPollInterval = 2 * time.Second
+
+// minUnixTime separates Unix timestamps from printer up-time values in the
+// integer time-at-* attributes: CUPS reports seconds since the epoch while
+// many printers report seconds since boot, which cannot be converted
+const minUnixTime = 1_000_000_000
+
+// jobAttributes are the attributes requested when querying jobs
+var jobAttributes = []string{
+	"job-id",
+	"job-uri",
+	"job-name",
+	"job-originating-user-name",
+	"job-state",
+	"job-state-reasons",
+	"job-state-message",
+	"job-impressions-completed",
+	"time-at-creation",
+	"time-at-processing",
+	"time-at-completed",
+	"date-time-at-creation",
+	"date-time-at-processing",
+	"date-time-at-completed",
+}
+
+// Job contains the status of a print job
+type Job struct {
+	ID                   int       `json:"id"`
+	URI                  string    `json:"uri,omitempty"`
+	Name                 string    `json:"name,omitempty"`
+	Owner                string    `json:"owner,omitempty"`
+	State                JobState  `json:"state"`
+	StateReasons         []string  `json:"state_reasons,omitempty"`
+	StateMessage         string    `json:"state_message,omitempty"`
+	ImpressionsCompleted int       `json:"impressions_completed"`
+	TimeAtCreation       time.Time `json:"time_at_creation,omitzero"`
+	TimeAtProcessing     time.Time `json:"time_at_processing,omitzero"`
+	TimeAtCompleted      time.Time `json:"time_at_completed,omitzero"`
+}
+
+// JobList is a list of jobs, e.g. the print queue returned by GetJobs
+type JobList []Job
+
+// String returns the job state as a human-readable string
+func (s JobState) String() string {
+	switch s {
+	case JobPending:
+		return "Pending"
+	case JobHeld:
+		return "Held"
+	case JobProcessing:
+		return "Processing"
+	case JobStopped:
+		return "Stopped"
+	case JobCanceled:
+		return "Canceled"
+	case JobAborted:
+		return "Aborted"
+	case JobCompleted:
+		return "Completed"
+	default:
+		return fmt.Sprintf("Unknown (%d)", int(s))
+	}
+}
+
+// MarshalText encodes the job state as its human-readable name in JSON
+func (s JobState) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// Terminal reports whether the job has finished and its state will not change
+func (s JobState) Terminal() bool {
+	return s == JobCanceled || s == JobAborted || s == JobCompleted
+}
+
+// GetJob retrieves the status of a print job via Get-Job-Attributes
+func (c *Client) GetJob(ctx context.Context, jobID int) (*Job, error) {
+	msg := c.newRequest(goipp.OpGetJobAttributes)
+	msg.Operation.Add(goipp.MakeAttr("job-id",
+		goipp.TagInteger, goipp.Integer(jobID)))
+	msg.Operation.Add(requestedAttributes(jobAttributes...))
+
+	resp, err := c.send(ctx, msg, nil)
+	if err != nil {
+		return nil, fmt.Errorf("getting job %d: %w", jobID, err)
+	}
+
+	return parseJob(resp.Job), nil
+}
+
+// GetJobs lists jobs on the printer via Get-Jobs. which selects
+// not-completed (the queue), completed or all jobs; myJobsOnly restricts the
+// list to jobs owned by the client's user; limit caps the number of jobs
+// returned when > 0.
+func (c *Client) GetJobs(ctx context.Context, which string, myJobsOnly bool, limit int) (JobList, error) {
+	if which == "" {
+		which = WhichJobsNotCompleted
+	}
+
+	msg := c.newRequest(goipp.OpGetJobs)
+	msg.Operation.Add(goipp.MakeAttr("which-jobs",
+		goipp.TagKeyword, goipp.String(which)))
+	if myJobsOnly {
+		msg.Operation.Add(goipp.MakeAttr("my-jobs",
+			goipp.TagBoolean, goipp.Boolean(true)))
+	}
+	if limit > 0 {
+		msg.Operation.Add(goipp.MakeAttr("limit",
+			goipp.TagInteger, goipp.Integer(limit)))
+	}
+	msg.Operation.Add(requestedAttributes(jobAttributes...))
+
+	resp, err := c.send(ctx, msg, nil)
+	if err != nil {
+		return nil, fmt.Errorf("listing jobs: %w", err)
+	}
+
+	// Each job is returned in its own job attributes group
+	jobs := JobList{}
+	for _, group := range resp.AttrGroups() {
+		if group.Tag == goipp.TagJobGroup && len(group.Attrs) > 0 {
+			jobs = append(jobs, *parseJob(group.Attrs))
+		}
+	}
+
+	return jobs, nil
+}
+
+// CancelJob cancels a print job via Cancel-Job
+func (c *Client) CancelJob(ctx context.Context, jobID int) error {
+	return c.jobOperation(ctx, goipp.OpCancelJob, jobID, "canceling")
+}
+
+// HoldJob holds a pending print job via Hold-Job so it is not printed
+// until released
+func (c *Client) HoldJob(ctx context.Context, jobID int) error {
+	return c.jobOperation(ctx, goipp.OpHoldJob, jobID, "holding")
+}
+
+// ReleaseJob releases a held print job via Release-Job
+func (c *Client) ReleaseJob(ctx context.Context, jobID int) error {
+	return c.jobOperation(ctx, goipp.OpReleaseJob, jobID, "releasing")
+}
+
+// RestartJob prints a retained job again via Restart-Job
+func (c *Client) RestartJob(ctx context.Context, jobID int) error {
+	return c.jobOperation(ctx, goipp.OpRestartJob, jobID, "restarting")
+}
+
+// CancelMyJobs cancels all jobs owned by the client's user via Cancel-My-Jobs
+func (c *Client) CancelMyJobs(ctx context.Context) error {
+	msg := c.newRequest(goipp.OpCancelMyJobs)
+
+	if _, err := c.send(ctx, msg, nil); err != nil {
+		return fmt.Errorf("canceling my jobs: %w", err)
+	}
+	return nil
+}
+
+// jobOperation sends a job operation that takes only a job-id
+func (c *Client) jobOperation(ctx context.Context, op goipp.Op, jobID int, verb string) error {
+	msg := c.newRequest(op)
+	msg.Operation.Add(goipp.MakeAttr("job-id",
+		goipp.TagInteger, goipp.Integer(jobID)))
+
+	if _, err := c.send(ctx, msg, nil); err != nil {
+		return fmt.Errorf("%s job %d: %w", verb, jobID, err)
+	}
+	return nil
+}
+
+// WaitJob polls a print job every interval until it reaches a terminal
+// state (completed, canceled or aborted) and returns its final status
+func (c *Client) WaitJob(ctx context.Context, jobID int, interval time.Duration) (*Job, error) {
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		job, err := c.GetJob(ctx, jobID)
+		if err != nil {
+			return nil, err
+		}
+		if job.State.Terminal() {
+			return job, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return job, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// parseJob builds a Job from a group of job attributes
+func parseJob(attrs goipp.Attributes) *Job {
+	job := &Job{
+		ID:                   getInt(attrs, "job-id"),
+		URI:                  getString(attrs, "job-uri"),
+		Name:                 getString(attrs, "job-name"),
+		Owner:                getString(attrs, "job-originating-user-name"),
+		State:                JobState(getInt(attrs, "job-state")),
+		StateMessage:         getString(attrs, "job-state-message"),
+		ImpressionsCompleted: getInt(attrs, "job-impressions-completed"),
+		TimeAtCreation:       getJobTime(attrs, "creation"),
+		TimeAtProcessing:     getJobTime(attrs, "processing"),
+		TimeAtCompleted:      getJobTime(attrs, "completed"),
+	}
+
+	if attr := findAttribute(attrs, "job-state-reasons"); attr != nil {
+		for _, v := range attr.Values {
+			job.StateReasons = append(job.StateReasons, v.V.String())
+		}
+	}
+
+	return job
+}
+
+// getJobTime returns a job timestamp from date-time-at-<event>, falling back
+// to the integer time-at-<event> when it holds a Unix timestamp
+func getJobTime(attrs goipp.Attributes, event string) time.Time {
+	if attr := findAttribute(attrs, "date-time-at-"+event); attr != nil && len(attr.Values) > 0 {
+		if t, ok := attr.Values[0].V.(goipp.Time); ok {
+			return t.Time
+		}
+	}
+
+	if seconds := getInt(attrs, "time-at-"+event); seconds >= minUnixTime {
+		return time.Unix(int64(seconds), 0)
+	}
+
+	return time.Time{}
+}
+
+// getInt returns the first value of an integer or enum attribute, or 0
+func getInt(attrs goipp.Attributes, name string) int {
+	if attr := findAttribute(attrs, name); attr != nil && len(attr.Values) > 0 {
+		if v, ok := attr.Values[0].V.(goipp.Integer); ok {
+			return int(v)
+		}
+	}
+	return 0
+}
+
+// getString returns the first value of an attribute as a string, or ""
+func getString(attrs goipp.Attributes, name string) string {
+	if attr := findAttribute(attrs, name); attr != nil && len(attr.Values) > 0 {
+		return attr.Values[0].V.String()
+	}
+	return ""
+}
+
+// requestedAttributes builds a requested-attributes operation attribute
+func requestedAttributes(names ...string) goipp.Attribute {
+	attr := goipp.MakeAttr("requested-attributes", goipp.TagKeyword, goipp.String(names[0]))
+	for _, name := range names[1:] {
+		attr.Values.Add(goipp.TagKeyword, goipp.String(name))
+	}
+	return attr
+}
@@ -0,0 +1,267 @@
+package printer
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/OpenPrinting/goipp"
+)
+
+// Test helper: create mock job attributes
+func createMockJobAttributes(id int, state JobState) goipp.Attributes {
+	return goipp.Attributes{
+		goipp.MakeAttr("job-id", goipp.TagInteger, goipp.Integer(id)),
+		goipp.MakeAttr("job-uri", goipp.TagURI, goipp.String("ipp://localhost/jobs/42")),
+		goipp.MakeAttr("job-name", goipp.TagName, goipp.String("photo.jpg")),
+		goipp.MakeAttr("job-originating-user-name", goipp.TagName, goipp.String("eric")),
+		goipp.MakeAttr("job-state", goipp.TagEnum, goipp.Integer(state)),
+		goipp.MakeAttr("job-state-reasons", goipp.TagKeyword,
+			goipp.String("job-printing"), goipp.String("media-low")),
+		goipp.MakeAttr("job-impressions-completed", goipp.TagInteger, goipp.Integer(3)),
+		goipp.MakeAttr("time-at-creation", goipp.TagInteger, goipp.Integer(1767225600)),
+		goipp.MakeAttr("time-at-processing", goipp.TagInteger, goipp.Integer(3600)), // Printer up-time
+		goipp.MakeAttr("time-at-completed", goipp.TagNoValue, goipp.Void{}),
+	}
+}
+
+func TestJobState_String(t *testing.T) {
+	tests := []struct {
+		state    JobState
+		expected string
+		terminal bool
+	}{
+		{JobPending, "Pending", false},
+		{JobHeld, "Held", false},
+		{JobProcessing, "Processing", false},
+		{JobStopped, "Stopped", false},
+		{JobCanceled, "Canceled", true},
+		{JobAborted, "Aborted", true},
+		{JobCompleted, "Completed", true},
+		{JobState(99), "Unknown (99)", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			if got := tt.state.String(); got != tt.expected {
+				t.Errorf("String() = %s, expected %s", got, tt.expected)
+			}
+			if got := tt.state.Terminal(); got != tt.terminal {
+				t.Errorf("Terminal() = %v, expected %v", got, tt.terminal)
+			}
+		})
+	}
+}
+
+func TestParseJob(t *testing.T) {
+	job := parseJob(createMockJobAttributes(42, JobProcessing))
+
+	if job.ID != 42 {
+		t.Errorf("expected ID 42, got %d", job.ID)
+	}
+	if job.Name != "photo.jpg" {
+		t.Errorf("expected name 'photo.jpg', got %s", job.Name)
+	}
+	if job.Owner != "eric" {
+		t.Errorf("expected owner 'eric', got %s", job.Owner)
+	}
+	if job.State != JobProcessing {
+		t.Errorf("expected state Processing, got %s", job.State)
+	}
+	if len(job.StateReasons) != 2 || job.StateReasons[1] != "media-low" {
+		t.Errorf("unexpected state reasons %v", job.StateReasons)
+	}
+	if job.ImpressionsCompleted != 3 {
+		t.Errorf("expected 3 impressions, got %d", job.ImpressionsCompleted)
+	}
+	if !job.TimeAtCreation.Equal(time.Unix(1767225600, 0)) {
+		t.Errorf("unexpected creation time %v", job.TimeAtCreation)
+	}
+	if !job.TimeAtProcessing.IsZero() {
+		t.Errorf("expected up-time based processing time to be ignored, got %v", job.TimeAtProcessing)
+	}
+	if !job.TimeAtCompleted.IsZero() {
+		t.Errorf("expected no completion time, got %v", job.TimeAtCompleted)
+	}
+}
+
+func TestParseJob_DateTime(t *testing.T) {
+	completed := time.Date(2026, 1, 3, 12, 30, 0, 0, time.UTC)
+	attrs := goipp.Attributes{
+		goipp.MakeAttr("job-id", goipp.TagInteger, goipp.Integer(7)),
+		goipp.MakeAttr("date-time-at-completed", goipp.TagDateTime, goipp.Time{Time: completed}),
+	}
+
+	job := parseJob(attrs)
+	if !job.TimeAtCompleted.Equal(completed) {
+		t.Errorf("expected completion time %v, got %v", completed, job.TimeAtCompleted)
+	}
+}
+
+func TestClient_GetJob(t *testing.T) {
+	var requestedID int
+	server := newTestIPPServer(t, func(_ *http.Request, req *goipp.Message) *goipp.Message {
+		requestedID = getInt(req.Operation, "job-id")
+		resp := goipp.NewResponse(goipp.DefaultVersion, goipp.StatusOk, req.RequestID)
+		resp.Job = createMockJobAttributes(requestedID, JobCompleted)
+		return resp
+	})
+
+	job, err := NewClient(server.URL).GetJob(context.Background(), 42)
+	if err != nil {
+		t.Fatalf("GetJob() error = %v", err)
+	}
+	if requestedID != 42 {
+		t.Errorf("expected job-id 42 in request, got %d", requestedID)
+	}
+	if job.State != JobCompleted {
+		t.Errorf("expected state Completed, got %s", job.State)
+	}
+}
+
+func TestClient_WaitJob(t *testing.T) {
+	states := []JobState{JobPending, JobProcessing, JobProcessing, JobAborted}
+	polls := 0
+	server := newTestIPPServer(t, func(_ *http.Request, req *goipp.Message) *goipp.Message {
+		state := states[min(polls, len(states)-1)]
+		polls++
+		resp := goipp.NewResponse(goipp.DefaultVersion, goipp.StatusOk, req.RequestID)
+		resp.Job = createMockJobAttributes(42, state)
+		return resp
+	})
+
+	job, err := NewClient(server.URL).WaitJob(context.Background(), 42, time.Millisecond)
+	if err != nil {
+		t.Fatalf("WaitJob() error = %v", err)
+	}
+	if job.State != JobAborted {
+		t.Errorf("expected final state Aborted, got %s", job.State)
+	}
+	if polls != len(states) {
+		t.Errorf("expected %d polls, got %d", len(states), polls)
+	}
+}
+
+func TestClient_WaitJob_Canceled(t *testing.T) {
+	server := newTestIPPServer(t, func(_ *http.Request, req *goipp.Message) *goipp.Message {
+		resp := goipp.NewResponse(goipp.DefaultVersion, goipp.StatusOk, req.RequestID)
+		resp.Job = createMockJobAttributes(42, JobProcessing)
+		return resp
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if _, err := NewClient(server.URL).WaitJob(ctx, 42, 5*time.Millisecond); err == nil {
+		t.Error("expected error when context expires, got nil")
+	}
+}
+
+func TestClient_GetJobs(t *testing.T) {
+	var got *goipp.Message
+	server := newTestIPPServer(t, func(_ *http.Request, req *goipp.Message) *goipp.Message {
+		got = req
+		groups := goipp.Groups{
+			{Tag: goipp.TagOperationGroup, Attrs: goipp.Attributes{
+				goipp.MakeAttr("attributes-charset", goipp.TagCharset, goipp.String("utf-8")),
+			}},
+			{Tag: goipp.TagJobGroup, Attrs: createMockJobAttributes(41, JobProcessing)},
+			{Tag: goipp.TagJobGroup, Attrs: createMockJobAttributes(42, JobPending)},
+		}
+		return goipp.NewMessageWithGroups(goipp.DefaultVersion, goipp.Code(goipp.StatusOk), req.RequestID, groups)
+	})
+
+	jobs, err := NewClient(server.URL).GetJobs(context.Background(), WhichJobsNotCompleted, true, 10)
+	if err != nil {
+		t.Fatalf("GetJobs() error = %v", err)
+	}
+
+	if len(jobs) != 2 {
+		t.Fatalf("expected 2 jobs, got %d", len(jobs))
+	}
+	if jobs[0].ID != 41 || jobs[1].ID != 42 {
+		t.Errorf("expected job IDs 41 and 42, got %d and %d", jobs[0].ID, jobs[1].ID)
+	}
+	if jobs[1].State != JobPending {
+		t.Errorf("expected second job Pending, got %s", jobs[1].State)
+	}
+
+	if v := getString(got.Operation, "which-jobs"); v != WhichJobsNotCompleted {
+		t.Errorf("expected which-jobs %q, got %q", WhichJobsNotCompleted, v)
+	}
+	if attr := findAttribute(got.Operation, "my-jobs"); attr == nil || attr.Values[0].V != goipp.Boolean(true) {
+		t.Errorf("expected my-jobs true, got %v", attr)
+	}
+	if v := getInt(got.Operation, "limit"); v != 10 {
+		t.Errorf("expected limit 10, got %d", v)
+	}
+}
+
+func TestClient_GetJobs_Empty(t *testing.T) {
+	server := newTestIPPServer(t, func(_ *http.Request, req *goipp.Message) *goipp.Message {
+		return goipp.NewResponse(goipp.DefaultVersion, goipp.StatusOk, req.RequestID)
+	})
+
+	jobs, err := NewClient(server.URL).GetJobs(context.Background(), "", false, 0)
+	if err != nil {
+		t.Fatalf("GetJobs() error = %v", err)
+	}
+	if len(jobs) != 0 {
+		t.Errorf("expected no jobs, got %d", len(jobs))
+	}
+}
+
+func TestClient_JobOperations(t *testing.T) {
+	var gotOp goipp.Op
+	var gotID int
+	server := newTestIPPServer(t, func(_ *http.Request, req *goipp.Message) *goipp.Message {
+		gotOp = goipp.Op(req.Code)
+		gotID = getInt(req.Operation, "job-id")
+		return goipp.NewResponse(goipp.DefaultVersion, goipp.StatusOk, req.RequestID)
+	})
+	c := NewClient(server.URL)
+	ctx := context.Background()
+
+	tests := []struct {
+		name   string
+		call   func() error
+		wantOp goipp.Op
+		wantID int
+	}{
+		{"cancel", func() error { return c.CancelJob(ctx, 11) }, goipp.OpCancelJob, 11},
+		{"hold", func() error { return c.HoldJob(ctx, 12) }, goipp.OpHoldJob, 12},
+		{"release", func() error { return c.ReleaseJob(ctx, 13) }, goipp.OpReleaseJob, 13},
+		{"restart", func() error { return c.RestartJob(ctx, 14) }, goipp.OpRestartJob, 14},
+		{"cancel my jobs", func() error { return c.CancelMyJobs(ctx) }, goipp.OpCancelMyJobs, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.call(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if gotOp != tt.wantOp {
+				t.Errorf("expected operation %s, got %s", tt.wantOp, gotOp)
+			}
+			if gotID != tt.wantID {
+				t.Errorf("expected job-id %d, got %d", tt.wantID, gotID)
+			}
+		})
+	}
+}
+
+func TestClient_JobOperations_Error(t *testing.T) {
+	server := newTestIPPServer(t, func(_ *http.Request, req *goipp.Message) *goipp.Message {
+		return goipp.NewResponse(goipp.DefaultVersion, goipp.StatusErrorNotPossible, req.RequestID)
+	})
+
+	err := NewClient(server.URL).CancelJob(context.Background(), 42)
+	if err == nil {
+		t.Fatal("expected error for client-error-not-possible, got nil")
+	}
+	if !strings.Contains(err.Error(), "canceling job 42") {
+		t.Errorf("expected error to mention the job, got %v", err)
+	}
+}
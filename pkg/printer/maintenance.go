@@ -0,0 +1,111 @@
+package printer
+
+import "strings"
+
+// Maintenance box status summaries returned by Maintenance.Status
+const (
+	MaintenanceUnknown    = "Not reported"
+	MaintenanceOK         = "OK"
+	MaintenanceAlmostFull = "Almost full"
+	MaintenanceFull       = "Full"
+)
+
+// MaintenanceBox is a waste ink marker: the maintenance box or waste ink
+// pad that collects ink from head cleaning and borderless printing
+type MaintenanceBox struct {
+	Name      string `json:"name"`
+	Level     int    `json:"level"`                // Percent full; negative when the printer cannot tell
+	HighLevel int    `json:"high_level,omitempty"` // Level at which the printer reports it almost full
+}
+
+// AlmostFull reports whether the box reached its high level
+func (b MaintenanceBox) AlmostFull() bool {
+	return b.HighLevel > 0 && b.Level >= b.HighLevel
+}
+
+// Maintenance is the state of the maintenance boxes, from the waste-ink
+// markers and the related printer-state-reasons. A full box stops the
+// printer, even in the middle of a job, until it is replaced.
+type Maintenance struct {
+	Boxes   []MaintenanceBox `json:"boxes,omitempty"`
+	Reasons []string         `json:"reasons,omitempty"` // e.g. marker-waste-almost-full-warning
+	Status  string           `json:"status"`            // One of the Maintenance* summaries
+}
+
+// newMaintenance builds the maintenance state from the waste ink markers and
+// all printer-state-reasons
+func newMaintenance(boxes []MaintenanceBox, reasons []string) Maintenance {
+	m := Maintenance{Boxes: boxes}
+	for _, reason := range reasons {
+		if isWasteReason(reason) {
+			m.Reasons = append(m.Reasons, reason)
+		}
+	}
+	m.Status = m.status()
+	return m
+}
+
+// status summarizes the boxes and reasons; the reasons win when the
+// printer reports a box as full before its level reaches 100%
+func (m Maintenance) status() string {
+	full, almostFull := false, false
+	for _, reason := range m.Reasons {
+		keyword := trimSeverity(reason)
+		switch {
+		case strings.HasSuffix(keyword, "almost-full"):
+			almostFull = true
+		case strings.HasSuffix(keyword, "full"):
+			full = true
+		}
+	}
+	for _, box := range m.Boxes {
+		full = full || box.Level >= 100
+		almostFull = almostFull || box.AlmostFull()
+	}
+
+	switch {
+	case full:
+		return MaintenanceFull
+	case almostFull:
+		return MaintenanceAlmostFull
+	case len(m.Boxes) > 0:
+		return MaintenanceOK
+	default:
+		return MaintenanceUnknown
+	}
+}
+
+// Advice returns what to do about the maintenance box, or "" when nothing
+// needs to be done
+func (m Maintenance) Advice() string {
+	switch m.Status {
+	case MaintenanceFull:
+		return "Replace the maintenance box; the printer stops until it is replaced"
+	case MaintenanceAlmostFull:
+		return "Order a replacement maintenance box"
+	default:
+		return ""
+	}
+}
+
+// isWasteMarker reports whether a marker-types keyword is a waste
+// receptacle (waste-ink, waste-toner, ...) rather than a supply
+func isWasteMarker(markerType string) bool {
+	return strings.HasPrefix(markerType, "waste-")
+}
+
+// isWasteReason reports whether a printer-state-reasons keyword is about the
+// maintenance box, e.g. marker-waste-almost-full-warning or
+// marker-waste-ink-receptacle-full-error
+func isWasteReason(reason string) bool {
+	return strings.HasPrefix(reason, "marker-waste-")
+}
+
+// trimSeverity removes the -error, -warning and -report suffix of a
+// printer-state-reasons keyword
+func trimSeverity(reason string) string {
+	for _, suffix := range []string{"-error", "-warning", "-report"} {
+		reason = strings.TrimSuffix(reason, suffix)
+	}
+	return reason
+}
@@ -0,0 +1,108 @@
+package printer
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/Eric-Eklund/epson-printing/pkg/printer/ipptest"
+	"github.com/OpenPrinting/goipp"
+)
+
+func TestNewMaintenance(t *testing.T) {
+	box := func(level int) []MaintenanceBox {
+		return []MaintenanceBox{{Name: "Maintenance Box", Level: level, HighLevel: 90}}
+	}
+
+	tests := []struct {
+		name        string
+		boxes       []MaintenanceBox
+		reasons     []string
+		wantStatus  string
+		wantReasons []string
+	}{
+		{name: "not reported", reasons: []string{"none"}, wantStatus: MaintenanceUnknown},
+		{name: "ok", boxes: box(40), reasons: []string{"marker-supply-low-warning"}, wantStatus: MaintenanceOK},
+		{name: "high level", boxes: box(90), wantStatus: MaintenanceAlmostFull},
+		{name: "level full", boxes: box(100), wantStatus: MaintenanceFull},
+		{name: "unknown level", boxes: box(-2), wantStatus: MaintenanceOK},
+		{
+			name: "almost full reason", boxes: box(70),
+			reasons:     []string{"media-low-report", "marker-waste-almost-full-warning"},
+			wantStatus:  MaintenanceAlmostFull,
+			wantReasons: []string{"marker-waste-almost-full-warning"},
+		},
+		{
+			name:        "full reason without markers",
+			reasons:     []string{"marker-waste-ink-receptacle-full-error"},
+			wantStatus:  MaintenanceFull,
+			wantReasons: []string{"marker-waste-ink-receptacle-full-error"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newMaintenance(tt.boxes, tt.reasons)
+			if m.Status != tt.wantStatus {
+				t.Errorf("Status = %s, want %s", m.Status, tt.wantStatus)
+			}
+			if !reflect.DeepEqual(m.Reasons, tt.wantReasons) {
+				t.Errorf("Reasons = %v, want %v", m.Reasons, tt.wantReasons)
+			}
+			if (m.Advice() != "") != (m.Status == MaintenanceFull || m.Status == MaintenanceAlmostFull) {
+				t.Errorf("Advice() = %q for status %s", m.Advice(), m.Status)
+			}
+		})
+	}
+}
+
+func TestGetMarkers_WasteInk(t *testing.T) {
+	p := ipptest.NewPrinter()
+	p.Markers = []ipptest.Marker{
+		{Name: "Black", Color: "#000000", Type: "ink-bottle", Level: 12, LowLevel: 15, HighLevel: 100},
+		{Name: "Maintenance Box", Color: "none", Type: "waste-ink", Level: 93, HighLevel: 90},
+		{Name: "Cyan", Color: "#00FFFF", Level: 60, LowLevel: 15, HighLevel: 100},
+	}
+	msg := &goipp.Message{Printer: p.PrinterAttributes()}
+
+	wantInks := []InkLevel{
+		{Name: "Black", Level: 12, Color: "#000000", LowLevel: 15},
+		{Name: "Cyan", Level: 60, Color: "#00FFFF", LowLevel: 15},
+	}
+	if got := getInkLevels(msg); !reflect.DeepEqual(got, wantInks) {
+		t.Errorf("getInkLevels() = %+v, want %+v", got, wantInks)
+	}
+	if !wantInks[0].Low() || wantInks[1].Low() {
+		t.Error("Low() should report Black only")
+	}
+
+	wantBoxes := []MaintenanceBox{{Name: "Maintenance Box", Level: 93, HighLevel: 90}}
+	if got := getMaintenanceBoxes(msg); !reflect.DeepEqual(got, wantBoxes) {
+		t.Errorf("getMaintenanceBoxes() = %+v, want %+v", got, wantBoxes)
+	}
+}
+
+func TestClient_Info_Maintenance(t *testing.T) {
+	p := ipptest.NewPrinter()
+	p.State = ipptest.PrinterStopped
+	p.StateReasons = []string{"marker-waste-full-error"}
+	p.Markers = append(p.Markers, ipptest.Marker{Name: "Maintenance Box", Type: "waste-ink", Level: 100, HighLevel: 90})
+	server := ipptest.NewServer(p)
+	defer server.Close()
+
+	info, err := NewClient(server.URI).Info(context.Background())
+	if err != nil {
+		t.Fatalf("Info() error = %v", err)
+	}
+	if len(info.InkLevels) != 6 {
+		t.Errorf("got %d ink levels, want 6 without the maintenance box", len(info.InkLevels))
+	}
+	want := Maintenance{
+		Boxes:   []MaintenanceBox{{Name: "Maintenance Box", Level: 100, HighLevel: 90}},
+		Reasons: []string{"marker-waste-full-error"},
+		Status:  MaintenanceFull,
+	}
+	if !reflect.DeepEqual(info.Maintenance, want) {
+		t.Errorf("Maintenance = %+v, want %+v", info.Maintenance, want)
+	}
+}
@@ -0,0 +1,191 @@
+package printer
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/netip"
+	"strings"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// mdnsAddr is the IPv4 multicast DNS group and port (RFC 6762)
+const mdnsAddr = "224.0.0.251:5353"
+
+// MDNSResolver browses DNS-SD services with one-shot multicast DNS queries
+// (RFC 6762, 5.1). Queries are sent from an ephemeral port, so responders
+// answer by unicast and no multicast group membership is needed.
+type MDNSResolver struct {
+	// Addr is the address queries are sent to (default 224.0.0.251:5353)
+	Addr string
+}
+
+// mdnsRecords collects the records received while browsing
+type mdnsRecords struct {
+	instances []string // PTR targets, in order of arrival
+	srv       map[string]dnsmessage.SRVResource
+	txt       map[string][]string
+	addrs     map[string][]netip.Addr
+}
+
+// Browse queries for instances of service (e.g. "_ipp._tcp") until ctx is
+// done and returns every instance that was resolved to a host
+func (r *MDNSResolver) Browse(ctx context.Context, service string) ([]ServiceEntry, error) {
+	addr := r.Addr
+	if addr == "" {
+		addr = mdnsAddr
+	}
+	target, err := net.ResolveUDPAddr("udp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("resolving mDNS address: %w", err)
+	}
+
+	conn, err := net.ListenUDP("udp", nil)
+	if err != nil {
+		return nil, fmt.Errorf("opening mDNS socket: %w", err)
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	domain := service + ".local."
+	if err := sendMDNSQuery(conn, target, domain, dnsmessage.TypePTR); err != nil {
+		return nil, err
+	}
+
+	// Stop reading when the browse time is up
+	stop := context.AfterFunc(ctx, func() {
+		_ = conn.SetReadDeadline(time.Now())
+	})
+	defer stop()
+
+	records := &mdnsRecords{
+		srv:   map[string]dnsmessage.SRVResource{},
+		txt:   map[string][]string{},
+		addrs: map[string][]netip.Addr{},
+	}
+	queried := map[string]bool{}
+	buf := make([]byte, 9000) // Maximum mDNS message size
+	for {
+		n, _, err := conn.ReadFromUDP(buf)
+		if err != nil {
+			if ctx.Err() != nil {
+				break
+			}
+			return nil, fmt.Errorf("reading mDNS response: %w", err)
+		}
+		if err := records.add(buf[:n], domain); err != nil {
+			continue // Ignore malformed responses from other devices
+		}
+
+		// Responders may leave out SRV and TXT records; ask for them
+		for _, instance := range records.instances {
+			if queried[instance] {
+				continue
+			}
+			queried[instance] = true
+			key := strings.ToLower(instance)
+			if _, ok := records.srv[key]; !ok {
+				_ = sendMDNSQuery(conn, target, instance, dnsmessage.TypeSRV)
+			}
+			if _, ok := records.txt[key]; !ok {
+				_ = sendMDNSQuery(conn, target, instance, dnsmessage.TypeTXT)
+			}
+		}
+	}
+
+	return records.entries(domain), nil
+}
+
+// sendMDNSQuery sends a single question
+func sendMDNSQuery(conn *net.UDPConn, target *net.UDPAddr, name string, qtype dnsmessage.Type) error {
+	qname, err := dnsmessage.NewName(name)
+	if err != nil {
+		return fmt.Errorf("invalid mDNS name %q: %w", name, err)
+	}
+
+	msg := dnsmessage.Message{
+		Questions: []dnsmessage.Question{{Name: qname, Type: qtype, Class: dnsmessage.ClassINET}},
+	}
+	packet, err := msg.Pack()
+	if err != nil {
+		return fmt.Errorf("encoding mDNS query: %w", err)
+	}
+	if _, err := conn.WriteToUDP(packet, target); err != nil {
+		return fmt.Errorf("sending mDNS query: %w", err)
+	}
+	return nil
+}
+
+// add stores the records of a response packet
+func (r *mdnsRecords) add(packet []byte, domain string) error {
+	var msg dnsmessage.Message
+	if err := msg.Unpack(packet); err != nil {
+		return err
+	}
+
+	for _, rr := range append(append(msg.Answers, msg.Authorities...), msg.Additionals...) {
+		name := strings.ToLower(rr.Header.Name.String())
+		switch body := rr.Body.(type) {
+		case *dnsmessage.PTRResource:
+			instance := body.PTR.String()
+			if name == strings.ToLower(domain) && !containsFold(r.instances, instance) {
+				r.instances = append(r.instances, instance)
+			}
+		case *dnsmessage.SRVResource:
+			r.srv[name] = *body
+		case *dnsmessage.TXTResource:
+			r.txt[name] = body.TXT
+		case *dnsmessage.AResource:
+			r.addrs[name] = appendAddr(r.addrs[name], netip.AddrFrom4(body.A))
+		case *dnsmessage.AAAAResource:
+			r.addrs[name] = appendAddr(r.addrs[name], netip.AddrFrom16(body.AAAA))
+		}
+	}
+	return nil
+}
+
+// entries returns the instances that have an SRV record
+func (r *mdnsRecords) entries(domain string) []ServiceEntry {
+	service := strings.TrimSuffix(domain, ".local.")
+
+	var entries []ServiceEntry
+	for _, instance := range r.instances {
+		srv, ok := r.srv[strings.ToLower(instance)]
+		if !ok {
+			continue
+		}
+		host := srv.Target.String()
+		entries = append(entries, ServiceEntry{
+			Instance: instanceName(instance, domain),
+			Service:  service,
+			Host:     host,
+			Port:     int(srv.Port),
+			Addrs:    r.addrs[strings.ToLower(host)],
+			TXT:      parseTXT(r.txt[strings.ToLower(instance)]),
+		})
+	}
+	return entries
+}
+
+// instanceName returns the user-visible part of a service instance name,
+// e.g. "EPSON ET-8550 Series" for "EPSON ET-8550 Series._ipp._tcp.local."
+// Labels are not escaped on the wire, so the name is used as is.
+func instanceName(instance, domain string) string {
+	if len(instance) > len(domain) && strings.EqualFold(instance[len(instance)-len(domain):], domain) {
+		return strings.TrimSuffix(instance[:len(instance)-len(domain)], ".")
+	}
+	return instance
+}
+
+// appendAddr adds an address unless it is already listed
+func appendAddr(addrs []netip.Addr, addr netip.Addr) []netip.Addr {
+	for _, a := range addrs {
+		if a == addr {
+			return addrs
+		}
+	}
+	return append(addrs, addr)
+}
@@ -0,0 +1,165 @@
+package printer
+
+import (
+	"cmp"
+	"fmt"
+	"strings"
+)
+
+// Model describes the hardware of a printer model. It is selected from the
+// printer-make-and-model attribute with ModelFor and decides report titles,
+// which paper sizes and trays validate and which profiles are offered.
+type Model struct {
+	Name         string   `json:"name"`                     // e.g. "EPSON ET-8550"
+	Inks         []string `json:"inks,omitempty"`           // Ink tanks in marker order
+	Trays        []string `json:"trays,omitempty"`          // Paper sources as used in profiles; empty means unknown
+	MaxPaperSize string   `json:"max_paper_size,omitempty"` // Largest paper the model feeds; empty means unknown
+	Borderless   bool     `json:"borderless"`               // Supports borderless printing
+
+	match string // Lowercase substring of printer-make-and-model that selects the model
+}
+
+// Built-in models
+var (
+	// ModelET8550 is the A3+ photo model this tool was written for
+	ModelET8550 = Model{
+		Name:         "EPSON ET-8550",
+		Inks:         []string{"Black", "Photo Black", "Cyan", "Magenta", "Yellow", "Gray"},
+		Trays:        []string{"Auto", "Main", "Photo", "Rear"},
+		MaxPaperSize: "13x19",
+		Borderless:   true,
+		match:        "et-8550",
+	}
+
+	// ModelET8500 is the A4 sibling of the ET-8550 with the same inks and trays
+	ModelET8500 = Model{
+		Name:         "EPSON ET-8500",
+		Inks:         []string{"Black", "Photo Black", "Cyan", "Magenta", "Yellow", "Gray"},
+		Trays:        []string{"Auto", "Main", "Photo", "Rear"},
+		MaxPaperSize: "Legal",
+		Borderless:   true,
+		match:        "et-8500",
+	}
+
+	// ModelET2850 is a four-color office model with a single front cassette
+	ModelET2850 = Model{
+		Name:         "EPSON ET-2850",
+		Inks:         []string{"Black", "Cyan", "Magenta", "Yellow"},
+		Trays:        []string{"Auto", "Main"},
+		MaxPaperSize: "Legal",
+		Borderless:   true,
+		match:        "et-2850",
+	}
+
+	// GenericModel is used for printers without a built-in model. Nothing
+	// is restricted; the printer's own capabilities still apply.
+	GenericModel = Model{
+		Name:       "IPP printer",
+		Borderless: true,
+	}
+)
+
+// models lists the built-in models in the order they are matched
+var models = []Model{ModelET8550, ModelET8500, ModelET2850}
+
+// Models returns the built-in models, without GenericModel
+func Models() []Model {
+	return append([]Model(nil), models...)
+}
+
+// ModelFor returns the built-in model matching a printer-make-and-model
+// value such as "EPSON ET-8550 Series", or GenericModel
+func ModelFor(makeAndModel string) Model {
+	makeAndModel = strings.ToLower(makeAndModel)
+	for _, m := range models {
+		if strings.Contains(makeAndModel, m.match) {
+			return m
+		}
+	}
+	return GenericModel
+}
+
+// LookupModel returns the built-in model with the given name, accepting
+// short names like "ET-2850"
+func LookupModel(name string) (Model, error) {
+	for _, m := range models {
+		if strings.EqualFold(m.Name, name) || strings.EqualFold(m.match, name) {
+			return m, nil
+		}
+	}
+
+	names := make([]string, 0, len(models))
+	for _, m := range models {
+		names = append(names, strings.ToUpper(m.match))
+	}
+	return Model{}, fmt.Errorf("unknown printer model %q (known: %s)", name, strings.Join(names, ", "))
+}
+
+// IsGeneric reports whether m is the fallback for unknown printers
+func (m Model) IsGeneric() bool {
+	return m.match == ""
+}
+
+// SupportsPaper reports whether the model can feed a paper size such as
+// "A4" or "13x19.Borderless". Sizes that are not known are accepted and
+// left to the printer.
+func (m Model) SupportsPaper(name string) bool {
+	size, borderless, ok := lookupPaperSize(name)
+	if !ok {
+		return true
+	}
+	if borderless && !m.Borderless {
+		return false
+	}
+	if m.MaxPaperSize == "" {
+		return true
+	}
+
+	limit, _, ok := lookupPaperSize(m.MaxPaperSize)
+	return !ok || (size.Width <= limit.Width && size.Height <= limit.Height)
+}
+
+// SupportsTray reports whether the model has the given paper source.
+// Unknown tray lists accept every tray.
+func (m Model) SupportsTray(tray string) bool {
+	return len(m.Trays) == 0 || containsFold(m.Trays, tray)
+}
+
+// SupportsProfile reports whether the paper size and tray of a profile
+// are available on the model
+func (m Model) SupportsProfile(profile PrintProfile) bool {
+	opts, err := GetPrintOptions(profile)
+	if err != nil {
+		return false
+	}
+	return m.SupportsPaper(opts.PaperSize) && (opts.Tray == "" || m.SupportsTray(opts.Tray))
+}
+
+// paperError explains why the model cannot use a paper size
+func (m Model) paperError(name string) error {
+	if _, borderless, _ := lookupPaperSize(name); borderless && !m.Borderless {
+		return fmt.Errorf("unsupported paper size %q: the %s cannot print borderless", name, m.Name)
+	}
+	return fmt.Errorf("unsupported paper size %q: the %s feeds paper up to %s", name, m.Name, m.MaxPaperSize)
+}
+
+// inkTitle returns the heading for the ink level section, e.g.
+// "INK LEVELS (6-COLOR SYSTEM)"
+func (m Model) inkTitle(levels int) string {
+	if len(m.Inks) > 0 {
+		levels = len(m.Inks)
+	}
+	if levels == 0 {
+		return "INK LEVELS"
+	}
+	return fmt.Sprintf("INK LEVELS (%d-COLOR SYSTEM)", levels)
+}
+
+// reportTitle returns the title of the status report for a printer
+func (m Model) reportTitle(makeAndModel string) string {
+	name := m.Name
+	if m.IsGeneric() {
+		name = cmp.Or(strings.TrimSpace(makeAndModel), "printer")
+	}
+	return strings.ToUpper(name) + " STATUS REPORT"
+}
@@ -0,0 +1,164 @@
+package printer
+
+import (
+	"testing"
+)
+
+func TestModelFor(t *testing.T) {
+	tests := []struct {
+		makeAndModel string
+		want         string
+	}{
+		{"EPSON ET-8550 Series", ModelET8550.Name},
+		{"Epson ET-8500 Series, driverless, cups-filters 1.28.17", ModelET8500.Name},
+		{"EPSON ET-2850 Series", ModelET2850.Name},
+		{"HP LaserJet Pro M404", GenericModel.Name},
+		{"", GenericModel.Name},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.makeAndModel, func(t *testing.T) {
+			if got := ModelFor(tt.makeAndModel).Name; got != tt.want {
+				t.Errorf("ModelFor(%q) = %s, want %s", tt.makeAndModel, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLookupModel(t *testing.T) {
+	for _, name := range []string{"ET-2850", "et-2850", "EPSON ET-2850"} {
+		if m, err := LookupModel(name); err != nil || m.Name != ModelET2850.Name {
+			t.Errorf("LookupModel(%q) = %s, %v; want %s", name, m.Name, err, ModelET2850.Name)
+		}
+	}
+	if _, err := LookupModel("ET-9999"); err == nil {
+		t.Error("LookupModel(ET-9999) expected error")
+	}
+}
+
+func TestModel_SupportsPaper(t *testing.T) {
+	noBorderless := Model{Name: "Office", MaxPaperSize: "A4"}
+
+	tests := []struct {
+		model Model
+		paper string
+		want  bool
+	}{
+		{ModelET8550, "13x19.Borderless", true},
+		{ModelET8550, "A3", true},
+		{ModelET8500, "13x19.Borderless", false},
+		{ModelET8500, "A3", false},
+		{ModelET8500, "Legal", true},
+		{ModelET2850, "A4.Borderless", true},
+		{ModelET2850, "na_super-b_13x19in", false},
+		{ModelET2850, "custom_roll_210x2000mm", true}, // Unknown sizes are left to the printer
+		{GenericModel, "13x19.Borderless", true},
+		{noBorderless, "A4.Borderless", false},
+		{noBorderless, "Letter", false}, // Letter is wider than A4
+		{noBorderless, "5x7", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.model.Name+" "+tt.paper, func(t *testing.T) {
+			if got := tt.model.SupportsPaper(tt.paper); got != tt.want {
+				t.Errorf("SupportsPaper(%s) = %v, want %v", tt.paper, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestModel_SupportsProfile(t *testing.T) {
+	tests := []struct {
+		model   Model
+		profile PrintProfile
+		want    bool
+	}{
+		{ModelET8550, ProfilePhotoA3PlusBorderlessMatte, true},
+		{ModelET8500, ProfilePhotoA3PlusBorderlessMatte, false},
+		{ModelET8500, ProfilePhotoA3BorderlessGlossy, false},
+		{ModelET8500, ProfilePhoto4x6BorderlessGlossy, true},
+		{ModelET2850, ProfilePhoto4x6BorderlessGlossy, false}, // No photo tray
+		{ModelET2850, ProfileDocumentNormal, true},
+		{GenericModel, ProfilePhotoA3PlusBorderlessMatte, true},
+		{ModelET8550, "no-such-profile", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.model.Name+" "+string(tt.profile), func(t *testing.T) {
+			if got := tt.model.SupportsProfile(tt.profile); got != tt.want {
+				t.Errorf("SupportsProfile(%s) = %v, want %v", tt.profile, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrintOptions_Validate_Model(t *testing.T) {
+	tests := []struct {
+		name    string
+		caps    *Capabilities
+		opts    PrintOptions
+		wantErr string
+	}{
+		{
+			name:    "A3+ on ET-2850",
+			caps:    &Capabilities{MakeAndModel: "EPSON ET-2850 Series"},
+			opts:    PrintOptions{PaperSize: "13x19.Borderless"},
+			wantErr: `unsupported paper size "13x19.Borderless": the EPSON ET-2850 feeds paper up to Legal`,
+		},
+		{
+			name:    "model trays when the printer lists none",
+			caps:    &Capabilities{MakeAndModel: "EPSON ET-2850 Series"},
+			opts:    PrintOptions{Tray: "Photo"},
+			wantErr: `unsupported tray "Photo" (supported: Auto, Main)`,
+		},
+		{
+			name: "printer trays take precedence over the model",
+			caps: &Capabilities{MakeAndModel: "EPSON ET-2850 Series", MediaSources: []string{"main", "rear"}},
+			opts: PrintOptions{Tray: "Rear"},
+		},
+		{
+			name: "A3+ on ET-8550",
+			caps: &Capabilities{MakeAndModel: "EPSON ET-8550 Series"},
+			opts: PrintOptions{PaperSize: "13x19.Borderless", Tray: "Rear"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.opts.Validate(tt.caps)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("Validate() error = %v, want %s", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestModel_Titles(t *testing.T) {
+	tests := []struct {
+		makeAndModel string
+		levels       int
+		wantTitle    string
+		wantInk      string
+	}{
+		{"EPSON ET-8550 Series", 6, "EPSON ET-8550 STATUS REPORT", "INK LEVELS (6-COLOR SYSTEM)"},
+		{"EPSON ET-2850 Series", 4, "EPSON ET-2850 STATUS REPORT", "INK LEVELS (4-COLOR SYSTEM)"},
+		{"Brother HL-L2350DW", 1, "BROTHER HL-L2350DW STATUS REPORT", "INK LEVELS (1-COLOR SYSTEM)"},
+		{"", 0, "PRINTER STATUS REPORT", "INK LEVELS"},
+	}
+
+	for _, tt := range tests {
+		m := ModelFor(tt.makeAndModel)
+		if got := m.reportTitle(tt.makeAndModel); got != tt.wantTitle {
+			t.Errorf("reportTitle(%q) = %s, want %s", tt.makeAndModel, got, tt.wantTitle)
+		}
+		if got := m.inkTitle(tt.levels); got != tt.wantInk {
+			t.Errorf("inkTitle(%d) for %q = %s, want %s", tt.levels, tt.makeAndModel, got, tt.wantInk)
+		}
+	}
+}
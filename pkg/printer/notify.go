@@ -0,0 +1,242 @@
+package printer
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/smtp"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// DefaultNotifyTimeout bounds each webhook request and SMTP session
+const DefaultNotifyTimeout = 30 * time.Second
+
+// Notifier delivers alerts, e.g. by email or to a webhook
+type Notifier interface {
+	Notify(ctx context.Context, alert Alert) error
+}
+
+// WriterNotifier writes each alert as a line of text, e.g. to os.Stdout
+type WriterNotifier struct {
+	W io.Writer
+}
+
+// Notify writes the alert
+func (n *WriterNotifier) Notify(_ context.Context, alert Alert) error {
+	_, err := fmt.Fprintln(n.W, alert)
+	return err
+}
+
+// ExecNotifier runs a shell command for each alert. The alert is passed as
+// JSON on stdin and in the environment variables ALERT_STATUS (ALERT,
+// REPEAT or RESOLVED), ALERT_PRINTER, ALERT_RULE and ALERT_MESSAGE.
+type ExecNotifier struct {
+	Command string // Run with sh -c
+}
+
+// Notify runs the command and fails if it exits with a non-zero status
+func (n *ExecNotifier) Notify(ctx context.Context, alert Alert) error {
+	data, err := json.Marshal(alert)
+	if err != nil {
+		return fmt.Errorf("encoding alert: %w", err)
+	}
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", n.Command)
+	cmd.Stdin = bytes.NewReader(data)
+	cmd.Env = append(os.Environ(),
+		"ALERT_STATUS="+alert.Status(),
+		"ALERT_PRINTER="+alert.Printer,
+		"ALERT_RULE="+alert.Rule,
+		"ALERT_MESSAGE="+alert.Message,
+	)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		if output := strings.TrimSpace(string(output)); output != "" {
+			return fmt.Errorf("running %q: %w: %s", n.Command, err, output)
+		}
+		return fmt.Errorf("running %q: %w", n.Command, err)
+	}
+	return nil
+}
+
+// WebhookNotifier posts each alert as JSON to a URL
+type WebhookNotifier struct {
+	URL        string
+	HTTPClient *http.Client // HTTP client used for requests (default: http.DefaultClient)
+	Header     http.Header  // Extra request headers, e.g. Authorization
+}
+
+// Notify posts the alert and fails unless the server answers with 2xx
+func (n *WebhookNotifier) Notify(ctx context.Context, alert Alert) error {
+	data, err := json.Marshal(alert)
+	if err != nil {
+		return fmt.Errorf("encoding alert: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, DefaultNotifyTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.URL, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("creating webhook request: %w", err)
+	}
+	for name, values := range n.Header {
+		req.Header[name] = values
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := n.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("posting to webhook: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook %s: HTTP %s", n.URL, resp.Status)
+	}
+	return nil
+}
+
+// SMTPNotifier emails each alert. STARTTLS is used when the server offers
+// it, and port 465 connects with TLS directly. Username and Password are
+// sent with PLAIN auth, which net/smtp only allows over TLS or to localhost.
+type SMTPNotifier struct {
+	Addr     string // Server host:port, e.g. smtp.example.com:587
+	Username string
+	Password string
+	From     string
+	To       []string
+
+	// TLSConfig is used for STARTTLS and port 465 (default: verify the
+	// server name)
+	TLSConfig *tls.Config
+}
+
+// Notify sends the alert as a plain text email
+func (n *SMTPNotifier) Notify(ctx context.Context, alert Alert) error {
+	if len(n.To) == 0 {
+		return fmt.Errorf("smtp: no recipients")
+	}
+	host, port, err := net.SplitHostPort(n.Addr)
+	if err != nil {
+		return fmt.Errorf("smtp: invalid address %q: %w", n.Addr, err)
+	}
+	tlsConfig := n.TLSConfig
+	if tlsConfig == nil {
+		tlsConfig = &tls.Config{ServerName: host}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, DefaultNotifyTimeout)
+	defer cancel()
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", n.Addr)
+	if err != nil {
+		return fmt.Errorf("smtp: %w", err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+	if port == "465" {
+		conn = tls.Client(conn, tlsConfig)
+	}
+
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		_ = conn.Close()
+		return fmt.Errorf("smtp: %w", err)
+	}
+	defer func() {
+		_ = client.Close()
+	}()
+
+	if err := n.send(client, tlsConfig, alert); err != nil {
+		return fmt.Errorf("smtp: %w", err)
+	}
+	return client.Quit()
+}
+
+// send runs the SMTP transaction for one alert
+func (n *SMTPNotifier) send(client *smtp.Client, tlsConfig *tls.Config, alert Alert) error {
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(tlsConfig); err != nil {
+			return err
+		}
+	}
+	if n.Username != "" {
+		host, _, _ := net.SplitHostPort(n.Addr)
+		if err := client.Auth(smtp.PlainAuth("", n.Username, n.Password, host)); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(n.From); err != nil {
+		return err
+	}
+	for _, to := range n.To {
+		if err := client.Rcpt(to); err != nil {
+			return err
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(n.message(alert)); err != nil {
+		_ = w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+// message formats the alert as an email with headers
+func (n *SMTPNotifier) message(alert Alert) []byte {
+	subject := fmt.Sprintf("[%s] %s: %s", alert.Status(), alert.Printer, alert.Message)
+	subject = strings.NewReplacer("\r", " ", "\n", " ").Replace(subject)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", n.From)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(n.To, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", alert.Time.Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+
+	fmt.Fprintf(&b, "%s\r\n\r\n", alert.Message)
+	fmt.Fprintf(&b, "Printer: %s\r\n", alert.Printer)
+	fmt.Fprintf(&b, "Rule:    %s\r\n", alert.Rule)
+	fmt.Fprintf(&b, "Time:    %s\r\n", alert.Time.Format("2006-01-02 15:04:05 MST"))
+	if info := alert.Info; info != nil {
+		fmt.Fprintf(&b, "State:   %s\r\n", info.State)
+		if info.StateMessage != "" {
+			fmt.Fprintf(&b, "Message: %s\r\n", info.StateMessage)
+		}
+		if len(info.InkLevels) > 0 {
+			b.WriteString("\r\nInk levels:\r\n")
+			for _, ink := range info.InkLevels {
+				// Printers report -1 when a level is unknown
+				level := fmt.Sprintf("%3d%%", ink.Level)
+				if ink.Level < 0 {
+					level = "unknown"
+				}
+				fmt.Fprintf(&b, "  %-14s %s\r\n", ink.Name, level)
+			}
+		}
+	}
+	return []byte(b.String())
+}
@@ -0,0 +1,228 @@
+package printer
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Test helper: an alert about a low Cyan tank
+func testAlert() Alert {
+	return Alert{
+		Time:    time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+		Printer: "photo",
+		Rule:    "ink<15",
+		Message: "Cyan ink low: 12% (below 15%)",
+		Info:    &Info{State: "Idle", InkLevels: []InkLevel{{Name: "Cyan", Level: 12}}},
+	}
+}
+
+// smtpMessage is a mail received by the fake SMTP server
+type smtpMessage struct {
+	from string
+	to   []string
+	data string
+}
+
+// Test helper: a minimal SMTP server on localhost that accepts one session
+// and sends the received message on the returned channel
+func newFakeSMTPServer(t *testing.T) (addr string, received <-chan smtpMessage) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = listener.Close()
+	})
+
+	messages := make(chan smtpMessage, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer func() {
+			_ = conn.Close()
+		}()
+
+		r := bufio.NewReader(conn)
+		reply := func(line string) {
+			_, _ = conn.Write([]byte(line + "\r\n"))
+		}
+		reply("220 localhost fake SMTP")
+
+		var msg smtpMessage
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			command := strings.TrimSpace(line)
+			switch verb := strings.ToUpper(strings.Fields(command + " ")[0]); verb {
+			case "EHLO", "HELO":
+				reply("250 localhost")
+			case "MAIL":
+				msg.from = strings.TrimPrefix(command, "MAIL FROM:")
+				reply("250 OK")
+			case "RCPT":
+				msg.to = append(msg.to, strings.TrimPrefix(command, "RCPT TO:"))
+				reply("250 OK")
+			case "DATA":
+				reply("354 End data with <CR><LF>.<CR><LF>")
+				var data strings.Builder
+				for {
+					line, err := r.ReadString('\n')
+					if err != nil || line == ".\r\n" {
+						break
+					}
+					data.WriteString(line)
+				}
+				msg.data = data.String()
+				reply("250 OK queued")
+			case "QUIT":
+				reply("221 Bye")
+				messages <- msg
+				return
+			default:
+				reply("502 Command not implemented")
+			}
+		}
+	}()
+	return listener.Addr().String(), messages
+}
+
+func TestSMTPNotifier(t *testing.T) {
+	addr, received := newFakeSMTPServer(t)
+
+	alert := testAlert()
+	alert.Info.InkLevels = append(alert.Info.InkLevels, InkLevel{Name: "Black", Level: -1})
+
+	n := &SMTPNotifier{Addr: addr, From: "printer@example.com", To: []string{"anna@example.com", "eric@example.com"}}
+	if err := n.Notify(context.Background(), alert); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+
+	msg := <-received
+	if msg.from != "<printer@example.com>" || len(msg.to) != 2 || msg.to[1] != "<eric@example.com>" {
+		t.Errorf("envelope = %s -> %v", msg.from, msg.to)
+	}
+	for _, want := range []string{
+		"Subject: [ALERT] photo: Cyan ink low: 12% (below 15%)\r\n",
+		"To: anna@example.com, eric@example.com\r\n",
+		"Rule:    ink<15\r\n",
+		"  Cyan            12%\r\n",
+		"  Black          unknown\r\n",
+	} {
+		if !strings.Contains(msg.data, want) {
+			t.Errorf("message does not contain %q:\n%s", want, msg.data)
+		}
+	}
+}
+
+func TestSMTPNotifier_Errors(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	closed := listener.Addr().String()
+	_ = listener.Close()
+
+	tests := []struct {
+		name string
+		n    *SMTPNotifier
+	}{
+		{"no recipients", &SMTPNotifier{Addr: closed, From: "printer@example.com"}},
+		{"invalid address", &SMTPNotifier{Addr: "localhost", To: []string{"anna@example.com"}}},
+		{"connection refused", &SMTPNotifier{Addr: closed, To: []string{"anna@example.com"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.n.Notify(context.Background(), testAlert()); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	var got Alert
+	var token string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token = r.Header.Get("Authorization")
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	n := &WebhookNotifier{URL: server.URL, Header: http.Header{"Authorization": {"Bearer secret"}}}
+	if err := n.Notify(context.Background(), testAlert()); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+	if got.Message != testAlert().Message || got.Info == nil || got.Info.InkLevels[0].Level != 12 {
+		t.Errorf("webhook received %+v", got)
+	}
+	if token != "Bearer secret" {
+		t.Errorf("Authorization = %q", token)
+	}
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "oops", http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+	if err := (&WebhookNotifier{URL: failing.URL}).Notify(context.Background(), testAlert()); err == nil {
+		t.Error("expected error for HTTP 500")
+	}
+}
+
+func TestExecNotifier(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "alert")
+	n := &ExecNotifier{Command: `printf '%s|%s|%s\n' "$ALERT_STATUS" "$ALERT_PRINTER" "$ALERT_RULE" > ` + out + ` && cat >> ` + out}
+	if err := n.Notify(context.Background(), testAlert()); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	line, body, _ := strings.Cut(string(data), "\n")
+	if line != "ALERT|photo|ink<15" {
+		t.Errorf("environment = %q", line)
+	}
+	var alert Alert
+	if err := json.Unmarshal([]byte(body), &alert); err != nil || alert.Message != testAlert().Message {
+		t.Errorf("stdin = %q (%v)", body, err)
+	}
+
+	err = (&ExecNotifier{Command: "echo no mail today >&2; exit 3"}).Notify(context.Background(), testAlert())
+	if err == nil || !strings.Contains(err.Error(), "no mail today") {
+		t.Errorf("Notify() error = %v, want the command output", err)
+	}
+}
+
+func TestWriterNotifier(t *testing.T) {
+	var buf bytes.Buffer
+	alert := testAlert()
+	alert.Resolved = true
+	if err := (&WriterNotifier{W: &buf}).Notify(context.Background(), alert); err != nil {
+		t.Fatal(err)
+	}
+	if want := "[photo] RESOLVED ink<15: Cyan ink low: 12% (below 15%)\n"; !strings.HasSuffix(buf.String(), want) {
+		t.Errorf("output = %q, want suffix %q", buf.String(), want)
+	}
+}
@@ -1,13 +1,20 @@
 package printer
 
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
 // PrintOptions contains all settings for a print job
 type PrintOptions struct {
-	PaperSize string // e.g., "4x6.Borderless", "A4.Borderless"
-	Tray      string // e.g., "Photo", "Main", "Rear", "Auto"
-	MediaType string // e.g., "photographic-glossy", "photographic-matte", "stationery"
-	Quality   int    // 3=draft, 4=high, 5=best
-	PageRange string // e.g., "1-5", "all", ":5", "5:"
-	Copies    int    // Number of copies (default: 1)
+	PaperSize string `json:"paper_size,omitempty"` // e.g., "4x6.Borderless", "A4.Borderless"
+	Tray      string `json:"tray,omitempty"`       // e.g., "Photo", "Main", "Rear", "Auto"
+	MediaType string `json:"media_type,omitempty"` // e.g., "photographic-glossy", "photographic-matte", "stationery"
+	Quality   int    `json:"quality,omitempty"`    // 3=draft, 4=high, 5=best
+	PageRange string `json:"page_range,omitempty"` // e.g., "1-5", "all", ":5", "5:", "1-3,7,10:"
+	Copies    int    `json:"copies,omitempty"`     // Number of copies (default: 1)
 }
 
 // DefaultPrintOptions returns the default profile options (test/draft on A4)
@@ -15,3 +22,117 @@ type PrintOptions struct {
 func DefaultPrintOptions() PrintOptions {
 	return MustGetPrintOptions(ProfileDefault)
 }
+
+// Overlay returns a copy of o with every non-zero field of overlay applied.
+// Zero values mean "unset", so partial options such as command-line
+// overrides or the settings of a profile that extends another can be
+// layered on top of complete ones.
+func (o PrintOptions) Overlay(overlay PrintOptions) PrintOptions {
+	if overlay.PaperSize != "" {
+		o.PaperSize = overlay.PaperSize
+	}
+	if overlay.Tray != "" {
+		o.Tray = overlay.Tray
+	}
+	if overlay.MediaType != "" {
+		o.MediaType = overlay.MediaType
+	}
+	if overlay.Quality != 0 {
+		o.Quality = overlay.Quality
+	}
+	if overlay.PageRange != "" {
+		o.PageRange = overlay.PageRange
+	}
+	if overlay.Copies != 0 {
+		o.Copies = overlay.Copies
+	}
+	return o
+}
+
+// Validate checks the options against the values the printer supports and
+// returns an error describing every unsupported setting, with a suggestion
+// when a value looks like a typo. Paper sizes are also checked against the
+// printer's Model, whose trays apply when the printer does not list any.
+// Unset options and other settings the printer does not report are not
+// checked.
+func (o PrintOptions) Validate(caps *Capabilities) error {
+	if caps == nil {
+		caps = &Capabilities{}
+	}
+
+	var errs []error
+	if _, err := ParsePageRanges(o.PageRange); err != nil {
+		errs = append(errs, err)
+	}
+	if err := o.validatePaperSize(caps); err != nil {
+		errs = append(errs, err)
+	}
+	// Trays are matched case-insensitively: profiles use "Main", IPP uses "main".
+	// The model's trays apply when the printer does not list its own.
+	trays := caps.MediaSources
+	if len(trays) == 0 {
+		trays = caps.Model().Trays
+	}
+	if o.Tray != "" && len(trays) > 0 && !containsFold(trays, o.Tray) {
+		errs = append(errs, unsupportedError("tray", o.Tray, trays))
+	}
+	if o.MediaType != "" && len(caps.MediaTypes) > 0 && !containsFold(caps.MediaTypes, o.MediaType) {
+		errs = append(errs, unsupportedError("media type", o.MediaType, caps.MediaTypes))
+	}
+	if o.Quality != 0 && len(caps.PrintQualities) > 0 && !slices.Contains(caps.PrintQualities, o.Quality) {
+		errs = append(errs, fmt.Errorf("unsupported quality %d (supported: %s)",
+			o.Quality, strings.Trim(fmt.Sprint(caps.PrintQualities), "[]")))
+	}
+
+	return errors.Join(errs...)
+}
+
+// validatePaperSize accepts a known paper name such as "A4.Borderless" whose
+// PWG media name the printer supports, or a PWG media name given directly
+func (o PrintOptions) validatePaperSize(caps *Capabilities) error {
+	if o.PaperSize == "" || containsFold(caps.Media, o.PaperSize) {
+		return nil
+	}
+
+	size, _, ok := lookupPaperSize(o.PaperSize)
+	if !ok {
+		return unsupportedError("paper size", o.PaperSize, paperNames(caps))
+	}
+	if len(caps.Media) > 0 && !containsFold(caps.Media, size.PWG) {
+		return fmt.Errorf("unsupported paper size %q: printer does not list %s (supported: %s)",
+			o.PaperSize, size.PWG, strings.Join(paperNames(caps), ", "))
+	}
+	if model := caps.Model(); !model.SupportsPaper(o.PaperSize) {
+		return model.paperError(o.PaperSize)
+	}
+
+	return nil
+}
+
+// paperNames returns the paper size names usable with the printer, with and
+// without the .Borderless suffix, followed by the printer's own media names
+func paperNames(caps *Capabilities) []string {
+	keys := make([]string, 0, len(paperSizes))
+	for key := range paperSizes {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	var names []string
+	for _, key := range keys {
+		size := paperSizes[key]
+		if len(caps.Media) == 0 || containsFold(caps.Media, size.PWG) {
+			names = append(names, size.Name, size.Name+".Borderless")
+		}
+	}
+	return append(names, caps.Media...)
+}
+
+// unsupportedError describes an unsupported value, suggesting the closest
+// supported one or listing them all
+func unsupportedError(setting, value string, supported []string) error {
+	if s := suggest(value, supported); s != "" {
+		return fmt.Errorf("unsupported %s %q (did you mean %q?)", setting, value, s)
+	}
+	return fmt.Errorf("unsupported %s %q (supported: %s)", setting, value, strings.Join(supported, ", "))
+}
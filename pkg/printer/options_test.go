@@ -76,3 +76,39 @@ func TestPrintOptionsCustomization(t *testing.T) {
 		t.Errorf("expected Copies 2, got %d", opts.Copies)
 	}
 }
+
+func TestPrintOptions_Overlay(t *testing.T) {
+	base := MustGetPrintOptions(ProfilePhotoA4BorderlessGlossy)
+
+	tests := []struct {
+		name    string
+		overlay PrintOptions
+		want    func(*PrintOptions)
+	}{
+		{"empty overlay", PrintOptions{}, func(*PrintOptions) {}},
+		{"single field", PrintOptions{Tray: "Rear"}, func(o *PrintOptions) { o.Tray = "Rear" }},
+		{
+			name:    "all fields",
+			overlay: PrintOptions{PaperSize: "A3", Tray: "Rear", MediaType: "photographic-matte", Quality: 4, PageRange: "1-2", Copies: 3},
+			want: func(o *PrintOptions) {
+				*o = PrintOptions{PaperSize: "A3", Tray: "Rear", MediaType: "photographic-matte", Quality: 4, PageRange: "1-2", Copies: 3}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := base
+			tt.want(&want)
+
+			if got := base.Overlay(tt.overlay); got != want {
+				t.Errorf("Overlay() = %+v, want %+v", got, want)
+			}
+		})
+	}
+
+	// The receiver is not modified
+	if base != MustGetPrintOptions(ProfilePhotoA4BorderlessGlossy) {
+		t.Error("Overlay() modified the base options")
+	}
+}
@@ -0,0 +1,100 @@
+package printer
+
+import (
+	"math"
+	"strings"
+
+	"github.com/OpenPrinting/goipp"
+)
+
+// borderlessSuffix marks a paper size for edge-to-edge printing, e.g. "A4.Borderless"
+const borderlessSuffix = ".borderless"
+
+// paperSize holds the physical dimensions of a paper size in portrait
+// orientation and its PWG 5101.1 self-describing media name
+type paperSize struct {
+	Name   string  // Display name used in profiles, e.g. "A4"
+	Width  float64 // mm
+	Height float64 // mm
+	PWG    string  // e.g. "iso_a4_210x297mm", as listed in media-supported
+}
+
+// paperSizes maps paper size names (lowercase, without .Borderless) to dimensions
+var paperSizes = map[string]paperSize{
+	"4x6":    {Name: "4x6", Width: 101.6, Height: 152.4, PWG: "na_index-4x6_4x6in"},
+	"5x7":    {Name: "5x7", Width: 127, Height: 177.8, PWG: "na_5x7_5x7in"},
+	"8x10":   {Name: "8x10", Width: 203.2, Height: 254, PWG: "na_govt-letter_8x10in"},
+	"a4":     {Name: "A4", Width: 210, Height: 297, PWG: "iso_a4_210x297mm"},
+	"a3":     {Name: "A3", Width: 297, Height: 420, PWG: "iso_a3_297x420mm"},
+	"13x19":  {Name: "13x19", Width: 330.2, Height: 482.6, PWG: "na_super-b_13x19in"}, // A3+
+	"letter": {Name: "Letter", Width: 215.9, Height: 279.4, PWG: "na_letter_8.5x11in"},
+	"legal":  {Name: "Legal", Width: 215.9, Height: 355.6, PWG: "na_legal_8.5x14in"},
+}
+
+// lookupPaperSize returns the dimensions for a paper size name such as
+// "A4" or "4x6.Borderless", and whether the name requests borderless printing
+func lookupPaperSize(name string) (size paperSize, borderless bool, ok bool) {
+	key := strings.ToLower(strings.TrimSpace(name))
+	if strings.HasSuffix(key, borderlessSuffix) {
+		key = strings.TrimSuffix(key, borderlessSuffix)
+		borderless = true
+	}
+
+	size, ok = paperSizes[key]
+	if ok {
+		return size, borderless, true
+	}
+
+	// Accept PWG media names such as "iso_a4_210x297mm" as well
+	for _, size := range paperSizes {
+		if strings.EqualFold(size.PWG, key) {
+			return size, borderless, true
+		}
+	}
+	return paperSize{}, borderless, false
+}
+
+// mediaCol builds the IPP media-col collection for the paper size, tray and
+// media type in opts. Known paper names are sent as PWG media-size-name and
+// media-size in hundredths of a millimetre; unknown names are passed through
+// as media-size-name. Borderless sizes set all four margins to 0.
+func mediaCol(opts PrintOptions) goipp.Attribute {
+	var col goipp.Collection
+
+	size, borderless, ok := lookupPaperSize(opts.PaperSize)
+	switch {
+	case ok:
+		col.Add(goipp.MakeAttr("media-size-name",
+			goipp.TagKeyword, goipp.String(size.PWG)))
+		col.Add(goipp.MakeAttrCollection("media-size",
+			goipp.MakeAttr("x-dimension", goipp.TagInteger, hundredthsOfMM(size.Width)),
+			goipp.MakeAttr("y-dimension", goipp.TagInteger, hundredthsOfMM(size.Height))))
+	case opts.PaperSize != "":
+		col.Add(goipp.MakeAttr("media-size-name",
+			goipp.TagKeyword, goipp.String(opts.PaperSize)))
+	}
+
+	// IPP keywords are lowercase; profiles use "Photo", "Main", ...
+	if opts.Tray != "" {
+		col.Add(goipp.MakeAttr("media-source",
+			goipp.TagKeyword, goipp.String(strings.ToLower(opts.Tray))))
+	}
+	if opts.MediaType != "" {
+		col.Add(goipp.MakeAttr("media-type",
+			goipp.TagKeyword, goipp.String(opts.MediaType)))
+	}
+
+	if borderless {
+		for _, side := range []string{"top", "bottom", "left", "right"} {
+			col.Add(goipp.MakeAttr("media-"+side+"-margin",
+				goipp.TagInteger, goipp.Integer(0)))
+		}
+	}
+
+	return goipp.MakeAttribute("media-col", goipp.TagBeginCollection, col)
+}
+
+// hundredthsOfMM converts millimetres to the 1/100 mm units used by media-size
+func hundredthsOfMM(mm float64) goipp.Integer {
+	return goipp.Integer(math.Round(mm * 100))
+}
@@ -0,0 +1,87 @@
+package printer
+
+import (
+	"testing"
+
+	"github.com/OpenPrinting/goipp"
+)
+
+func TestLookupPaperSize(t *testing.T) {
+	tests := []struct {
+		name           string
+		paper          string
+		wantOK         bool
+		wantBorderless bool
+		wantWidth      float64
+		wantHeight     float64
+	}{
+		{"A4", "A4", true, false, 210, 297},
+		{"A4 borderless", "A4.Borderless", true, true, 210, 297},
+		{"4x6 borderless", "4x6.Borderless", true, true, 101.6, 152.4},
+		{"A3+ borderless", "13x19.Borderless", true, true, 330.2, 482.6},
+		{"case insensitive", "letter.borderless", true, true, 215.9, 279.4},
+		{"PWG name", "iso_a3_297x420mm", true, false, 297, 420},
+		{"PWG name borderless", "na_index-4x6_4x6in.Borderless", true, true, 101.6, 152.4},
+		{"unknown", "A0", false, false, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			size, borderless, ok := lookupPaperSize(tt.paper)
+			if ok != tt.wantOK {
+				t.Fatalf("lookupPaperSize(%q) ok = %v, want %v", tt.paper, ok, tt.wantOK)
+			}
+			if borderless != tt.wantBorderless {
+				t.Errorf("lookupPaperSize(%q) borderless = %v, want %v", tt.paper, borderless, tt.wantBorderless)
+			}
+			if size.Width != tt.wantWidth || size.Height != tt.wantHeight {
+				t.Errorf("lookupPaperSize(%q) = %vx%v, want %vx%v",
+					tt.paper, size.Width, size.Height, tt.wantWidth, tt.wantHeight)
+			}
+		})
+	}
+}
+
+func TestMediaCol(t *testing.T) {
+	tests := []struct {
+		name string
+		opts PrintOptions
+		want string
+	}{
+		{
+			name: "borderless photo",
+			opts: PrintOptions{PaperSize: "4x6.Borderless", Tray: "Photo", MediaType: "photographic-glossy"},
+			want: "{media-size-name=na_index-4x6_4x6in media-size={x-dimension=10160 y-dimension=15240} " +
+				"media-source=photo media-type=photographic-glossy " +
+				"media-top-margin=0 media-bottom-margin=0 media-left-margin=0 media-right-margin=0}",
+		},
+		{
+			name: "bordered A4",
+			opts: PrintOptions{PaperSize: "A4", Tray: "Main", MediaType: "stationery"},
+			want: "{media-size-name=iso_a4_210x297mm media-size={x-dimension=21000 y-dimension=29700} " +
+				"media-source=main media-type=stationery}",
+		},
+		{
+			name: "unknown name passed through",
+			opts: PrintOptions{PaperSize: "jis_b5_182x257mm"},
+			want: "{media-size-name=jis_b5_182x257mm}",
+		},
+		{
+			name: "unset options",
+			opts: PrintOptions{},
+			want: "{}",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attr := mediaCol(tt.opts)
+			if attr.Name != "media-col" || attr.Values[0].T != goipp.TagBeginCollection {
+				t.Fatalf("mediaCol() = %s %s, want media-col collection", attr.Name, attr.Values[0].T)
+			}
+			if got := attr.Values[0].V.String(); got != tt.want {
+				t.Errorf("mediaCol() =\n  %s\nwant\n  %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,10 +1,12 @@
 package printer
 
 import (
-	"bytes"
+	"context"
 	"fmt"
-	"net/http"
+	"io"
+	"math"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -12,38 +14,107 @@ import (
 )
 
 // PrintPDF sends a PDF file to the printer via IPP
-func PrintPDF(printerURI, pdfPath string, opts PrintOptions) (int, error) {
-	// Read PDF file
-	pdfData, err := os.ReadFile(pdfPath)
+func PrintPDF(printerURI, pdfPath string, opts PrintOptions) (*JobResult, error) {
+	return NewClient(printerURI).PrintPDF(context.Background(), pdfPath, opts)
+}
+
+// PrintReader streams a document of the given MIME type to the printer via IPP
+func PrintReader(ctx context.Context, printerURI, name, format string, r io.Reader, opts PrintOptions) (*JobResult, error) {
+	return NewClient(printerURI).Print(ctx, name, format, r, opts)
+}
+
+// PrintPDF sends a PDF file to the printer
+func (c *Client) PrintPDF(ctx context.Context, pdfPath string, opts PrintOptions) (*JobResult, error) {
+	file, err := os.Open(pdfPath)
 	if err != nil {
-		return 0, fmt.Errorf("reading PDF file: %w", err)
+		return nil, fmt.Errorf("opening PDF file: %w", err)
 	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	return c.Print(ctx, jobName(pdfPath), FormatPDF, file, opts)
+}
 
-	// Get filename for job name
-	filename := pdfPath
-	if stat, err := os.Stat(pdfPath); err == nil {
-		filename = stat.Name()
+// jobName returns the file name used as the IPP job-name for a path
+func jobName(path string) string {
+	if stat, err := os.Stat(path); err == nil {
+		return stat.Name()
 	}
+	return path
+}
 
+// Print streams a document of the given MIME type to the printer as an
+// IPP Print-Job. The encoded IPP header and the document body are sent with
+// chunked transfer encoding, so the document is never buffered in memory.
+// Settings the printer ignored or substituted are listed in the result's
+// Substituted field; the job is still printed with the printer's defaults.
+// When the printer rejects the job, the result is returned with the error.
+func (c *Client) Print(ctx context.Context, name, format string, r io.Reader, opts PrintOptions) (*JobResult, error) {
 	// Build IPP Print-Job request
-	msg := goipp.NewRequest(goipp.DefaultVersion, goipp.OpPrintJob, 1)
-
-	// Operation attributes
-	msg.Operation.Add(goipp.MakeAttr("attributes-charset",
-		goipp.TagCharset, goipp.String("utf-8")))
-	msg.Operation.Add(goipp.MakeAttr("attributes-natural-language",
-		goipp.TagLanguage, goipp.String("en-US")))
-	msg.Operation.Add(goipp.MakeAttr("printer-uri",
-		goipp.TagURI, goipp.String(printerURI)))
-	msg.Operation.Add(goipp.MakeAttr("requesting-user-name",
-		goipp.TagName, goipp.String(os.Getenv("USER"))))
+	msg := c.newRequest(goipp.OpPrintJob)
 	msg.Operation.Add(goipp.MakeAttr("job-name",
-		goipp.TagName, goipp.String(filename)))
+		goipp.TagName, goipp.String(name)))
 	msg.Operation.Add(goipp.MakeAttr("document-format",
-		goipp.TagMimeType, goipp.String("application/pdf")))
+		goipp.TagMimeType, goipp.String(format)))
 
 	// Job attributes - print settings
-	// Note: Using CUPS-style attribute names for compatibility
+	if c.LegacyAttributes {
+		addLegacyJobAttributes(msg, opts)
+	} else {
+		addJobAttributes(msg, opts)
+	}
+
+	// Add page ranges unless all pages are selected
+	ranges, err := ParsePageRanges(opts.PageRange)
+	if err != nil {
+		return nil, err
+	}
+	if len(ranges) > 0 {
+		attr := goipp.Attribute{Name: "page-ranges"}
+		for _, r := range ranges {
+			attr.Values.Add(goipp.TagRange, r)
+		}
+		msg.Job.Add(attr)
+	}
+
+	if c.DryRun {
+		return &JobResult{}, c.describeRequest(msg, r)
+	}
+
+	respMsg, err := c.send(ctx, msg, r)
+	if respMsg == nil {
+		return nil, fmt.Errorf("sending print job: %w", err)
+	}
+
+	return parseJobResult(respMsg), err
+}
+
+// addJobAttributes adds standard IPP job attributes for the options, as
+// understood by IPP Everywhere printers and CUPS
+func addJobAttributes(msg *goipp.Message, opts PrintOptions) {
+	msg.Job.Add(mediaCol(opts))
+	if opts.Quality > 0 {
+		msg.Job.Add(goipp.MakeAttr("print-quality",
+			goipp.TagEnum, goipp.Integer(opts.Quality)))
+	}
+	if opts.Copies > 0 {
+		msg.Job.Add(goipp.MakeAttr("copies",
+			goipp.TagInteger, goipp.Integer(opts.Copies)))
+	}
+
+	// Fill the page edge to edge when borderless, otherwise fit within margins
+	scaling := "fit"
+	if _, borderless, _ := lookupPaperSize(opts.PaperSize); borderless {
+		scaling = "fill"
+	}
+	msg.Job.Add(goipp.MakeAttr("print-scaling",
+		goipp.TagKeyword, goipp.String(scaling)))
+}
+
+// addLegacyJobAttributes adds the CUPS PPD-style job attributes used before
+// media-col support, for CUPS queues that expect PPD option names
+func addLegacyJobAttributes(msg *goipp.Message, opts PrintOptions) {
 	msg.Job.Add(goipp.MakeAttr("PageSize",
 		goipp.TagKeyword, goipp.String(opts.PaperSize)))
 	msg.Job.Add(goipp.MakeAttr("InputSlot",
@@ -56,111 +127,101 @@ func PrintPDF(printerURI, pdfPath string, opts PrintOptions) (int, error) {
 		goipp.TagInteger, goipp.Integer(opts.Copies)))
 	msg.Job.Add(goipp.MakeAttr("fit-to-page",
 		goipp.TagBoolean, goipp.Boolean(true)))
+}
 
-	// Add page range if not "all"
-	if opts.PageRange != "" && opts.PageRange != "all" {
-		pageRangeIPP := convertPageRange(opts.PageRange)
-		msg.Job.Add(goipp.MakeAttr("page-ranges",
-			goipp.TagRange, pageRangeIPP))
-	}
-
-	// Encode request
-	request, err := msg.EncodeBytes()
-	if err != nil {
-		return 0, fmt.Errorf("encoding IPP request: %w", err)
-	}
-
-	// Append PDF data to request
-	requestWithPDF := append(request, pdfData...)
-
-	// Send HTTP request
-	resp, err := http.Post(printerURI, goipp.ContentType, bytes.NewBuffer(requestWithPDF))
-	if err != nil {
-		return 0, fmt.Errorf("sending print job: %w", err)
-	}
-	defer func() {
-		_ = resp.Body.Close()
-	}()
-
-	// Decode response
-	var respMsg goipp.Message
-	err = respMsg.Decode(resp.Body)
-	if err != nil {
-		return 0, fmt.Errorf("decoding response: %w", err)
+// ParsePageRanges parses a comma-separated page selection into IPP ranges.
+// Each part is a single page ("7"), a range ("1-3"), the first pages (":5")
+// or an open-ended range to the last page ("10:"), e.g. "1-3,7,10:".
+// An empty selection or "all" returns nil, meaning all pages. Ranges are
+// sorted and must not overlap.
+func ParsePageRanges(pageRange string) ([]goipp.Range, error) {
+	pageRange = strings.TrimSpace(pageRange)
+	if pageRange == "" || strings.EqualFold(pageRange, "all") {
+		return nil, nil
 	}
 
-	// Extract job ID
-	jobID := 0
-	if attr := getAttribute(&respMsg, "job-id"); attr != nil && len(attr.Values) > 0 {
-		if idVal, ok := attr.Values[0].V.(goipp.Integer); ok {
-			jobID = int(idVal)
+	var ranges []goipp.Range
+	for _, part := range strings.Split(pageRange, ",") {
+		r, err := parsePageRange(strings.TrimSpace(part))
+		if err != nil {
+			return nil, fmt.Errorf("invalid page range %q: %w", pageRange, err)
 		}
+		ranges = append(ranges, r)
 	}
 
-	// Check for errors
-	status := goipp.Status(respMsg.Code)
-	if status != goipp.StatusOk && status != goipp.StatusOkIgnoredOrSubstituted {
-		return jobID, fmt.Errorf("printer returned error: %s", status.String())
+	// IPP requires ascending, non-overlapping ranges
+	slices.SortFunc(ranges, func(a, b goipp.Range) int {
+		return a.Lower - b.Lower
+	})
+	for i := 1; i < len(ranges); i++ {
+		if ranges[i].Lower <= ranges[i-1].Upper {
+			return nil, fmt.Errorf("invalid page range %q: %s overlaps %s",
+				pageRange, formatPageRange(ranges[i-1]), formatPageRange(ranges[i]))
+		}
 	}
 
-	return jobID, nil
+	return ranges, nil
 }
 
-// convertPageRange converts page range notation to IPP range format
-// Supports: "1" (single page), "1-5" (range), ":5" (first 5), "5:" (from 5 to end)
-// Note: Comma-separated pages "1,3,5" will print only the first page listed
-func convertPageRange(pageRange string) goipp.Range {
-	pageRange = strings.TrimSpace(pageRange)
-
-	// Single page: "1"
-	if !strings.ContainsAny(pageRange, "-:,") {
-		if page, err := strconv.Atoi(pageRange); err == nil && page > 0 {
-			return goipp.Range{Lower: page, Upper: page}
-		}
+// parsePageRange parses a single part of a page selection
+func parsePageRange(part string) (goipp.Range, error) {
+	if part == "" {
+		return goipp.Range{}, fmt.Errorf("empty page range")
 	}
 
-	// Range with dash: "1-5"
-	if strings.Contains(pageRange, "-") {
-		parts := strings.Split(pageRange, "-")
-		if len(parts) == 2 {
-			lower, err1 := strconv.Atoi(strings.TrimSpace(parts[0]))
-			upper, err2 := strconv.Atoi(strings.TrimSpace(parts[1]))
-			if err1 == nil && err2 == nil && lower > 0 && upper > 0 {
-				return goipp.Range{Lower: lower, Upper: upper}
-			}
+	var lower, upper string
+	switch {
+	case strings.Contains(part, ":"):
+		lower, upper, _ = strings.Cut(part, ":")
+		if lower == "" && upper == "" {
+			return goipp.Range{}, fmt.Errorf("%q needs a page before or after ':'", part)
 		}
+		if lower == "" {
+			lower = "1"
+		}
+	case strings.Contains(part, "-"):
+		lower, upper, _ = strings.Cut(part, "-")
+		if lower == "" || upper == "" {
+			return goipp.Range{}, fmt.Errorf("%q needs a page on both sides of '-' (use ':' for open ranges)", part)
+		}
+	default:
+		lower, upper = part, part
 	}
 
-	// Range with colon: ":5" or "5:"
-	if strings.Contains(pageRange, ":") {
-		parts := strings.Split(pageRange, ":")
-		if len(parts) == 2 {
-			// ":5" - first 5 pages (1-5)
-			if parts[0] == "" {
-				if upper, err := strconv.Atoi(strings.TrimSpace(parts[1])); err == nil && upper > 0 {
-					return goipp.Range{Lower: 1, Upper: upper}
-				}
-			}
-			// "5:" - from page 5 to end
-			if parts[1] == "" {
-				if lower, err := strconv.Atoi(strings.TrimSpace(parts[0])); err == nil && lower > 0 {
-					return goipp.Range{Lower: lower, Upper: 999}
-				}
-			}
+	first, err := parsePage(lower)
+	if err != nil {
+		return goipp.Range{}, err
+	}
+	last := math.MaxInt32 // Open-ended: to the last page
+	if upper != "" {
+		if last, err = parsePage(upper); err != nil {
+			return goipp.Range{}, err
 		}
 	}
+	if first > last {
+		return goipp.Range{}, fmt.Errorf("%q ends before it starts", part)
+	}
 
-	// Comma-separated: "1,3,5" - print only first page
-	// (IPP ranges don't support non-contiguous pages well)
-	if strings.Contains(pageRange, ",") {
-		parts := strings.Split(pageRange, ",")
-		if len(parts) > 0 {
-			if page, err := strconv.Atoi(strings.TrimSpace(parts[0])); err == nil && page > 0 {
-				return goipp.Range{Lower: page, Upper: page}
-			}
-		}
+	return goipp.Range{Lower: first, Upper: last}, nil
+}
+
+// parsePage parses a page number, which must be 1 or greater
+func parsePage(s string) (int, error) {
+	page, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil || page < 1 || page > math.MaxInt32 {
+		return 0, fmt.Errorf("%q is not a valid page number", s)
 	}
+	return page, nil
+}
 
-	// Default: all pages
-	return goipp.Range{Lower: 1, Upper: 999}
+// formatPageRange formats a range in the notation accepted by ParsePageRanges
+func formatPageRange(r goipp.Range) string {
+	switch {
+	case r.Upper == math.MaxInt32:
+		return fmt.Sprintf("%d:", r.Lower)
+	case r.Lower == r.Upper:
+		return strconv.Itoa(r.Lower)
+	default:
+		return fmt.Sprintf("%d-%d", r.Lower, r.Upper)
+	}
 }
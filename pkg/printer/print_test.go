@@ -1,87 +1,139 @@
 package printer
 
 import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/Eric-Eklund/epson-printing/pkg/printer/ipptest"
+	"github.com/OpenPrinting/goipp"
 )
 
-func TestConvertPageRange(t *testing.T) {
+// Test helper: start an IPP server that accepts Print-Job requests.
+// The decoded request is passed to onJob together with the document body,
+// which onJob must consume.
+func newTestPrintServer(t testing.TB, onJob func(r *http.Request, req *goipp.Message, doc io.Reader)) *httptest.Server {
+	t.Helper()
+
+	return newTestIPPServer(t, func(r *http.Request, req *goipp.Message) *goipp.Message {
+		onJob(r, req, r.Body)
+
+		resp := goipp.NewResponse(goipp.DefaultVersion, goipp.StatusOk, req.RequestID)
+		resp.Job.Add(goipp.MakeAttr("job-id", goipp.TagInteger, goipp.Integer(42)))
+		return resp
+	})
+}
+
+// zeroReader is an endless source of zero bytes for generating large documents
+type zeroReader struct{}
+
+func (zeroReader) Read(p []byte) (int, error) {
+	clear(p)
+	return len(p), nil
+}
+
+func TestParsePageRanges(t *testing.T) {
 	tests := []struct {
 		name      string
 		pageRange string
-		wantLower int
-		wantUpper int
+		want      []goipp.Range
 	}{
-		{
-			name:      "all pages",
-			pageRange: "all",
-			wantLower: 1,
-			wantUpper: 999,
-		},
-		{
-			name:      "single page",
-			pageRange: "1",
-			wantLower: 1,
-			wantUpper: 1,
-		},
-		{
-			name:      "single page 5",
-			pageRange: "5",
-			wantLower: 5,
-			wantUpper: 5,
-		},
-		{
-			name:      "range 1-5",
-			pageRange: "1-5",
-			wantLower: 1,
-			wantUpper: 5,
-		},
-		{
-			name:      "range 3-10",
-			pageRange: "3-10",
-			wantLower: 3,
-			wantUpper: 10,
-		},
-		{
-			name:      "first 5 pages",
-			pageRange: ":5",
-			wantLower: 1,
-			wantUpper: 5,
-		},
-		{
-			name:      "from page 5",
-			pageRange: "5:",
-			wantLower: 5,
-			wantUpper: 999,
-		},
-		{
-			name:      "specific pages (comma) - only first",
-			pageRange: "1,3,5",
-			wantLower: 1,
-			wantUpper: 1,
-		},
-		{
-			name:      "empty string",
-			pageRange: "",
-			wantLower: 1,
-			wantUpper: 999,
-		},
+		{"all pages", "all", nil},
+		{"all pages uppercase", "ALL", nil},
+		{"empty string", "", nil},
+		{"single page", "1", []goipp.Range{{Lower: 1, Upper: 1}}},
+		{"range 1-5", "1-5", []goipp.Range{{Lower: 1, Upper: 5}}},
+		{"first 5 pages", ":5", []goipp.Range{{Lower: 1, Upper: 5}}},
+		{"from page 5", "5:", []goipp.Range{{Lower: 5, Upper: math.MaxInt32}}},
+		{"comma list", "1,3,5", []goipp.Range{{Lower: 1, Upper: 1}, {Lower: 3, Upper: 3}, {Lower: 5, Upper: 5}}},
+		{"mixed forms", "1-3,7,10:", []goipp.Range{{Lower: 1, Upper: 3}, {Lower: 7, Upper: 7}, {Lower: 10, Upper: math.MaxInt32}}},
+		{"spaces", " 1 - 3 , 7 ", []goipp.Range{{Lower: 1, Upper: 3}, {Lower: 7, Upper: 7}}},
+		{"sorted", "7,1-3", []goipp.Range{{Lower: 1, Upper: 3}, {Lower: 7, Upper: 7}}},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := convertPageRange(tt.pageRange)
+			got, err := ParsePageRanges(tt.pageRange)
+			if err != nil {
+				t.Fatalf("ParsePageRanges(%q) error = %v", tt.pageRange, err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("ParsePageRanges(%q) = %v, want %v", tt.pageRange, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParsePageRanges_Errors(t *testing.T) {
+	tests := []struct {
+		name      string
+		pageRange string
+		wantErr   string
+	}{
+		{"not a number", "abc", `"abc" is not a valid page number`},
+		{"zero", "0", `"0" is not a valid page number`},
+		{"negative", "-3", `needs a page on both sides of '-'`},
+		{"reversed", "5-1", `"5-1" ends before it starts`},
+		{"empty part", "1,,3", "empty page range"},
+		{"trailing comma", "1,", "empty page range"},
+		{"bare colon", ":", `needs a page before or after ':'`},
+		{"overlap", "1-5,3", "1-5 overlaps 3"},
+		{"overlap open-ended", "10:,12", "10: overlaps 12"},
+		{"too many separators", "1-2-3", `"2-3" is not a valid page number`},
+	}
 
-			if result.Lower != tt.wantLower {
-				t.Errorf("convertPageRange(%q).Lower = %d, want %d", tt.pageRange, result.Lower, tt.wantLower)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParsePageRanges(tt.pageRange)
+			if err == nil {
+				t.Fatalf("ParsePageRanges(%q) expected error", tt.pageRange)
 			}
-			if result.Upper != tt.wantUpper {
-				t.Errorf("convertPageRange(%q).Upper = %d, want %d", tt.pageRange, result.Upper, tt.wantUpper)
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ParsePageRanges(%q) error = %v, want it to contain %q", tt.pageRange, err, tt.wantErr)
 			}
 		})
 	}
 }
 
+func TestClient_Print_PageRanges(t *testing.T) {
+	var got []goipp.Range
+	server := newTestPrintServer(t, func(_ *http.Request, req *goipp.Message, doc io.Reader) {
+		if attr := findAttribute(req.Job, "page-ranges"); attr != nil {
+			for _, v := range attr.Values {
+				got = append(got, v.V.(goipp.Range))
+			}
+		}
+		_, _ = io.Copy(io.Discard, doc)
+	})
+
+	opts := DefaultPrintOptions()
+	opts.PageRange = "1-3,7,10:"
+	if _, err := NewClient(server.URL).Print(context.Background(), "doc.pdf", FormatPDF, strings.NewReader("%PDF"), opts); err != nil {
+		t.Fatalf("Print() error = %v", err)
+	}
+
+	want := []goipp.Range{{Lower: 1, Upper: 3}, {Lower: 7, Upper: 7}, {Lower: 10, Upper: math.MaxInt32}}
+	if !slices.Equal(got, want) {
+		t.Errorf("page-ranges = %v, want %v", got, want)
+	}
+
+	// Malformed ranges are rejected before anything is sent
+	opts.PageRange = "1-x"
+	if _, err := NewClient(server.URL).Print(context.Background(), "doc.pdf", FormatPDF, strings.NewReader("%PDF"), opts); err == nil {
+		t.Error("Print() expected error for malformed page range")
+	}
+}
+
 func TestPrintOptions_Validation(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -126,15 +178,197 @@ func TestPrintOptions_Validation(t *testing.T) {
 		},
 	}
 
+	caps := createMockCapabilities()
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			// Just verify options can be created without panic
-			// Actual validation would happen in PrintPDF
-			_ = tt.opts
+			err := tt.opts.Validate(caps)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
 		})
 	}
 }
 
+func TestPrintReader(t *testing.T) {
+	document := bytes.Repeat([]byte("%PDF-1.7 test document\n"), 1000)
+
+	var received []byte
+	var chunked bool
+	var jobName, format string
+	server := newTestPrintServer(t, func(r *http.Request, req *goipp.Message, doc io.Reader) {
+		chunked = r.ContentLength == -1 && len(r.TransferEncoding) > 0 && r.TransferEncoding[0] == "chunked"
+		if attr := findAttribute(req.Operation, "job-name"); attr != nil {
+			jobName = attr.Values[0].V.String()
+		}
+		if attr := findAttribute(req.Operation, "document-format"); attr != nil {
+			format = attr.Values[0].V.String()
+		}
+		received, _ = io.ReadAll(doc)
+	})
+
+	result, err := PrintReader(context.Background(), server.URL, "test.pdf", FormatPDF,
+		bytes.NewReader(document), DefaultPrintOptions())
+	if err != nil {
+		t.Fatalf("PrintReader() error = %v", err)
+	}
+
+	if result.JobID != 42 {
+		t.Errorf("expected job ID 42, got %d", result.JobID)
+	}
+	if len(result.Substituted) != 0 {
+		t.Errorf("expected no substituted attributes, got %v", result.Substituted)
+	}
+	if !chunked {
+		t.Error("expected request to use chunked transfer encoding")
+	}
+	if jobName != "test.pdf" {
+		t.Errorf("expected job-name 'test.pdf', got %q", jobName)
+	}
+	if format != FormatPDF {
+		t.Errorf("expected document-format %q, got %q", FormatPDF, format)
+	}
+	if !bytes.Equal(received, document) {
+		t.Errorf("document corrupted: received %d bytes, sent %d", len(received), len(document))
+	}
+}
+
+func TestClient_Print_JobAttributes(t *testing.T) {
+	opts := MustGetPrintOptions(ProfilePhoto4x6BorderlessGlossy)
+
+	tests := []struct {
+		name       string
+		legacy     bool
+		want       []string
+		notWant    []string
+		qualityTag goipp.Tag
+	}{
+		{
+			name:       "IPP media-col",
+			qualityTag: goipp.TagEnum,
+			want:       []string{"media-col", "print-quality", "copies", "print-scaling"},
+			notWant:    []string{"PageSize", "InputSlot", "media", "fit-to-page"},
+		},
+		{
+			name:       "legacy CUPS attributes",
+			legacy:     true,
+			qualityTag: goipp.TagInteger,
+			want:       []string{"PageSize", "InputSlot", "media", "print-quality", "copies", "fit-to-page"},
+			notWant:    []string{"media-col", "print-scaling"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var job goipp.Attributes
+			server := newTestPrintServer(t, func(_ *http.Request, req *goipp.Message, doc io.Reader) {
+				job = req.Job
+				_, _ = io.Copy(io.Discard, doc)
+			})
+
+			c := NewClient(server.URL)
+			c.LegacyAttributes = tt.legacy
+			if _, err := c.Print(context.Background(), "photo.pdf", FormatPDF, strings.NewReader("%PDF"), opts); err != nil {
+				t.Fatalf("Print() error = %v", err)
+			}
+
+			for _, name := range tt.want {
+				if findAttribute(job, name) == nil {
+					t.Errorf("expected job attribute %s", name)
+				}
+			}
+			for _, name := range tt.notWant {
+				if findAttribute(job, name) != nil {
+					t.Errorf("unexpected job attribute %s", name)
+				}
+			}
+			if attr := findAttribute(job, "print-quality"); attr != nil && attr.Values[0].T != tt.qualityTag {
+				t.Errorf("print-quality tag = %s, want %s", attr.Values[0].T, tt.qualityTag)
+			}
+		})
+	}
+}
+
+func TestPrintReader_Canceled(t *testing.T) {
+	server := newTestPrintServer(t, func(_ *http.Request, _ *goipp.Message, doc io.Reader) {
+		_, _ = io.Copy(io.Discard, doc)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := PrintReader(ctx, server.URL, "test.pdf", FormatPDF,
+		bytes.NewReader([]byte("%PDF-1.7")), DefaultPrintOptions())
+	if err == nil {
+		t.Fatal("expected error for canceled context, got nil")
+	}
+}
+
+// TestClient_PrintPDF_EmulatedPrinter exercises the whole submission path
+// against an emulated printer
+func TestClient_PrintPDF_EmulatedPrinter(t *testing.T) {
+	p := ipptest.NewPrinter()
+	server := ipptest.NewServer(p)
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "calendar.pdf")
+	if err := os.WriteFile(path, []byte("%PDF-1.7 calendar"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	c := NewClient(server.URI)
+	c.UserName = "alice"
+	opts := MustGetPrintOptions(ProfilePhotoA3PlusBorderlessMatte)
+	opts.PageRange = "1-12"
+
+	result, err := c.PrintPDF(context.Background(), path, opts)
+	if err != nil {
+		t.Fatalf("PrintPDF() error = %v", err)
+	}
+	if len(result.Substituted) != 0 {
+		t.Errorf("unexpected substituted attributes: %v", result.Substituted)
+	}
+
+	job, ok := p.Job(result.JobID)
+	if !ok {
+		t.Fatalf("job %d not received", result.JobID)
+	}
+	if job.Name != "calendar.pdf" || job.User != "alice" || job.Format != FormatPDF {
+		t.Errorf("job = %s by %s as %s", job.Name, job.User, job.Format)
+	}
+	if len(job.Documents) != 1 || string(job.Documents[0]) != "%PDF-1.7 calendar" {
+		t.Errorf("documents = %q", job.Documents)
+	}
+	if findAttribute(job.Attributes, "media-col") == nil || findAttribute(job.Attributes, "page-ranges") == nil {
+		t.Errorf("job attributes missing media-col or page-ranges: %v", job.Attributes)
+	}
+
+	final, err := c.WaitJob(context.Background(), result.JobID, time.Millisecond)
+	if err != nil {
+		t.Fatalf("WaitJob() error = %v", err)
+	}
+	if final.State != JobCompleted {
+		t.Errorf("final state = %s, want Completed", final.State)
+	}
+}
+
+func TestClient_Print_EmulatedPrinterSubstitutes(t *testing.T) {
+	p := ipptest.NewPrinter()
+	p.MediaTypes = []string{"stationery"}
+	server := ipptest.NewServer(p)
+	defer server.Close()
+
+	opts := MustGetPrintOptions(ProfilePhoto4x6BorderlessGlossy)
+	result, err := NewClient(server.URI).Print(context.Background(), "photo.pdf", FormatPDF, strings.NewReader("%PDF"), opts)
+	if err != nil {
+		t.Fatalf("Print() error = %v", err)
+	}
+
+	want := []SubstitutedAttribute{{Name: "media-col.media-type", Value: "photographic-glossy"}}
+	if !slices.Equal(result.Substituted, want) {
+		t.Errorf("Substituted = %v, want %v", result.Substituted, want)
+	}
+}
+
 // TestPrintPDF_Integration tests actual printing to a real printer
 // This test is skipped by default and only runs when explicitly enabled
 func TestPrintPDF_Integration(t *testing.T) {
@@ -165,9 +399,32 @@ func TestPrintTestPDF_Integration(t *testing.T) {
 }
 
 // Benchmark convertPageRange
-func BenchmarkConvertPageRange(b *testing.B) {
+func BenchmarkParsePageRanges(b *testing.B) {
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		convertPageRange("1-5")
+		_, _ = ParsePageRanges("1-3,7,10:")
+	}
+}
+
+// BenchmarkPrintReader streams documents of increasing size to a local
+// server. Allocations per operation (B/op) stay flat as the document grows
+// because the body is never buffered in memory.
+func BenchmarkPrintReader(b *testing.B) {
+	server := newTestPrintServer(b, func(_ *http.Request, _ *goipp.Message, doc io.Reader) {
+		_, _ = io.Copy(io.Discard, doc)
+	})
+	opts := DefaultPrintOptions()
+
+	for _, size := range []int64{1 << 20, 16 << 20, 64 << 20} {
+		b.Run(fmt.Sprintf("%dMB", size>>20), func(b *testing.B) {
+			b.SetBytes(size)
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				doc := io.LimitReader(zeroReader{}, size)
+				if _, err := PrintReader(context.Background(), server.URL, "bench.pdf", FormatPDF, doc, opts); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
 	}
 }
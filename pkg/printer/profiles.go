@@ -1,6 +1,9 @@
 package printer
 
-import "fmt"
+import (
+	"fmt"
+	"slices"
+)
 
 // PrintProfile represents a named print configuration profile
 type PrintProfile string
@@ -96,173 +99,96 @@ const (
 	ProfileDocumentBest PrintProfile = "document-best"
 )
 
-// printProfiles is the registry of all available print profiles
-var printProfiles = map[PrintProfile]PrintOptions{
+// builtinProfile is a predefined profile. Like a UserProfile, a variant
+// extends another built-in profile and only sets what it overrides.
+type builtinProfile struct {
+	Extends PrintProfile
+	PrintOptions
+}
+
+// builtinProfiles defines the predefined profiles. The glossy profile of
+// each paper size extends the default profile with its own paper, tray,
+// media and quality; the matte and semi-gloss variants extend the glossy
+// profile of their size.
+var builtinProfiles = map[PrintProfile]builtinProfile{
 	// Default profile - A4 draft on plain paper
-	ProfileDefault: {
+	ProfileDefault: {PrintOptions: PrintOptions{
 		PaperSize: "A4",
 		Tray:      "Main",
 		MediaType: "stationery",
 		Quality:   3, // Draft
 		PageRange: "all",
 		Copies:    1,
-	},
+	}},
 
 	// 4x6 Borderless profiles
-	ProfilePhoto4x6BorderlessGlossy: {
+	ProfilePhoto4x6BorderlessGlossy: {Extends: ProfileDefault, PrintOptions: PrintOptions{
 		PaperSize: "4x6.Borderless",
 		Tray:      "Photo",
 		MediaType: "photographic-glossy",
 		Quality:   5, // Best quality for photos
-		PageRange: "all",
-		Copies:    1,
-	},
-	ProfilePhoto4x6BorderlessMatte: {
-		PaperSize: "4x6.Borderless",
-		Tray:      "Photo",
-		MediaType: "photographic-matte",
-		Quality:   5,
-		PageRange: "all",
-		Copies:    1,
-	},
-	ProfilePhoto4x6BorderlessSemiGloss: {
-		PaperSize: "4x6.Borderless",
-		Tray:      "Photo",
-		MediaType: "photographic-semi-gloss",
-		Quality:   5,
-		PageRange: "all",
-		Copies:    1,
-	},
+	}},
+	ProfilePhoto4x6BorderlessMatte:     {Extends: ProfilePhoto4x6BorderlessGlossy, PrintOptions: PrintOptions{MediaType: "photographic-matte"}},
+	ProfilePhoto4x6BorderlessSemiGloss: {Extends: ProfilePhoto4x6BorderlessGlossy, PrintOptions: PrintOptions{MediaType: "photographic-semi-gloss"}},
 
 	// 5x7 Borderless profiles
-	ProfilePhoto5x7BorderlessGlossy: {
+	ProfilePhoto5x7BorderlessGlossy: {Extends: ProfileDefault, PrintOptions: PrintOptions{
 		PaperSize: "5x7.Borderless",
 		Tray:      "Photo",
 		MediaType: "photographic-glossy",
 		Quality:   5,
-		PageRange: "all",
-		Copies:    1,
-	},
-	ProfilePhoto5x7BorderlessMatte: {
-		PaperSize: "5x7.Borderless",
-		Tray:      "Photo",
-		MediaType: "photographic-matte",
-		Quality:   5,
-		PageRange: "all",
-		Copies:    1,
-	},
-	ProfilePhoto5x7BorderlessSemiGloss: {
-		PaperSize: "5x7.Borderless",
-		Tray:      "Photo",
-		MediaType: "photographic-semi-gloss",
-		Quality:   5,
-		PageRange: "all",
-		Copies:    1,
-	},
+	}},
+	ProfilePhoto5x7BorderlessMatte:     {Extends: ProfilePhoto5x7BorderlessGlossy, PrintOptions: PrintOptions{MediaType: "photographic-matte"}},
+	ProfilePhoto5x7BorderlessSemiGloss: {Extends: ProfilePhoto5x7BorderlessGlossy, PrintOptions: PrintOptions{MediaType: "photographic-semi-gloss"}},
 
 	// A4 Borderless profiles
-	ProfilePhotoA4BorderlessGlossy: {
+	ProfilePhotoA4BorderlessGlossy: {Extends: ProfileDefault, PrintOptions: PrintOptions{
 		PaperSize: "A4.Borderless",
 		Tray:      "Auto",
 		MediaType: "photographic-glossy",
 		Quality:   5,
-		PageRange: "all",
-		Copies:    1,
-	},
-	ProfilePhotoA4BorderlessMatte: {
-		PaperSize: "A4.Borderless",
-		Tray:      "Auto",
-		MediaType: "photographic-matte",
-		Quality:   5,
-		PageRange: "all",
-		Copies:    1,
-	},
-	ProfilePhotoA4BorderlessSemiGloss: {
-		PaperSize: "A4.Borderless",
-		Tray:      "Auto",
-		MediaType: "photographic-semi-gloss",
-		Quality:   5,
-		PageRange: "all",
-		Copies:    1,
-	},
+	}},
+	ProfilePhotoA4BorderlessMatte:     {Extends: ProfilePhotoA4BorderlessGlossy, PrintOptions: PrintOptions{MediaType: "photographic-matte"}},
+	ProfilePhotoA4BorderlessSemiGloss: {Extends: ProfilePhotoA4BorderlessGlossy, PrintOptions: PrintOptions{MediaType: "photographic-semi-gloss"}},
 
-	// A3 Borderless profiles
-	ProfilePhotoA3BorderlessGlossy: {
-		PaperSize: "A3.Borderless",
-		Tray:      "Rear", // A3 requires rear tray
-		MediaType: "photographic-glossy",
-		Quality:   5,
-		PageRange: "all",
-		Copies:    1,
-	},
-	ProfilePhotoA3BorderlessMatte: {
+	// A3 Borderless profiles (A3 requires the rear tray)
+	ProfilePhotoA3BorderlessGlossy: {Extends: ProfileDefault, PrintOptions: PrintOptions{
 		PaperSize: "A3.Borderless",
 		Tray:      "Rear",
-		MediaType: "photographic-matte",
-		Quality:   5,
-		PageRange: "all",
-		Copies:    1,
-	},
-	ProfilePhotoA3BorderlessSemiGloss: {
-		PaperSize: "A3.Borderless",
-		Tray:      "Rear",
-		MediaType: "photographic-semi-gloss",
-		Quality:   5,
-		PageRange: "all",
-		Copies:    1,
-	},
-
-	// A3+ Borderless profiles (13x19)
-	ProfilePhotoA3PlusBorderlessGlossy: {
-		PaperSize: "13x19.Borderless",
-		Tray:      "Rear", // A3+ requires rear tray
 		MediaType: "photographic-glossy",
 		Quality:   5,
-		PageRange: "all",
-		Copies:    1,
-	},
-	ProfilePhotoA3PlusBorderlessMatte: {
-		PaperSize: "13x19.Borderless",
-		Tray:      "Rear",
-		MediaType: "photographic-matte",
-		Quality:   5,
-		PageRange: "all",
-		Copies:    1,
-	},
-	ProfilePhotoA3PlusBorderlessSemiGloss: {
+	}},
+	ProfilePhotoA3BorderlessMatte:     {Extends: ProfilePhotoA3BorderlessGlossy, PrintOptions: PrintOptions{MediaType: "photographic-matte"}},
+	ProfilePhotoA3BorderlessSemiGloss: {Extends: ProfilePhotoA3BorderlessGlossy, PrintOptions: PrintOptions{MediaType: "photographic-semi-gloss"}},
+
+	// A3+ Borderless profiles (13x19), also from the rear tray
+	ProfilePhotoA3PlusBorderlessGlossy: {Extends: ProfileDefault, PrintOptions: PrintOptions{
 		PaperSize: "13x19.Borderless",
 		Tray:      "Rear",
-		MediaType: "photographic-semi-gloss",
+		MediaType: "photographic-glossy",
 		Quality:   5,
-		PageRange: "all",
-		Copies:    1,
-	},
+	}},
+	ProfilePhotoA3PlusBorderlessMatte:     {Extends: ProfilePhotoA3PlusBorderlessGlossy, PrintOptions: PrintOptions{MediaType: "photographic-matte"}},
+	ProfilePhotoA3PlusBorderlessSemiGloss: {Extends: ProfilePhotoA3PlusBorderlessGlossy, PrintOptions: PrintOptions{MediaType: "photographic-semi-gloss"}},
 
 	// Document profiles
-	ProfileDocumentDraft: {
-		PaperSize: "A4",
-		Tray:      "Main",
-		MediaType: "stationery",
-		Quality:   3, // Draft quality
-		PageRange: "all",
-		Copies:    1,
-	},
-	ProfileDocumentNormal: {
-		PaperSize: "A4",
-		Tray:      "Main",
-		MediaType: "stationery",
-		Quality:   4, // Normal/high quality
-		PageRange: "all",
-		Copies:    1,
-	},
-	ProfileDocumentBest: {
-		PaperSize: "A4",
-		Tray:      "Main",
-		MediaType: "stationery-coated",
-		Quality:   5, // Best quality
-		PageRange: "all",
-		Copies:    1,
-	},
+	ProfileDocumentDraft:  {Extends: ProfileDefault},
+	ProfileDocumentNormal: {Extends: ProfileDocumentDraft, PrintOptions: PrintOptions{Quality: 4}},                                 // Normal/high quality
+	ProfileDocumentBest:   {Extends: ProfileDocumentDraft, PrintOptions: PrintOptions{MediaType: "stationery-coated", Quality: 5}}, // Best quality
+}
+
+// printProfiles is the registry of all available print profiles, with the
+// settings of built-in and user profiles fully resolved
+var printProfiles = make(map[PrintProfile]PrintOptions, len(builtinProfiles))
+
+// resolveBuiltinProfile applies the overrides of a built-in profile on top
+// of the profile it extends
+func resolveBuiltinProfile(name PrintProfile) PrintOptions {
+	profile := builtinProfiles[name]
+	if profile.Extends == "" {
+		return profile.PrintOptions
+	}
+	return resolveBuiltinProfile(profile.Extends).Overlay(profile.PrintOptions)
 }
 
 // GetPrintOptions returns the PrintOptions for a given profile
@@ -317,6 +243,16 @@ func GetProfileDescription(profile PrintProfile) string {
 		opts.PaperSize, opts.MediaType, opts.Tray, opts.Quality)
 }
 
+// MaxBuiltinProfileID is the highest ID of the predefined profiles.
+// User-defined profiles are numbered from MaxBuiltinProfileID+1.
+const MaxBuiltinProfileID = 18
+
+// IsBuiltinProfile reports whether name is one of the predefined profiles
+func IsBuiltinProfile(name PrintProfile) bool {
+	id, exists := profileNameToID[name]
+	return exists && id <= MaxBuiltinProfileID
+}
+
 // profileIDs maps numeric IDs to profile names for easier command-line usage
 var profileIDs = map[int]PrintProfile{
 	0: ProfileDefault,
@@ -355,7 +291,14 @@ var profileIDs = map[int]PrintProfile{
 // profileNameToID is the reverse mapping for looking up IDs by name
 var profileNameToID map[PrintProfile]int
 
+// profileExtends records the base profile of registered user profiles
+var profileExtends = map[PrintProfile]PrintProfile{}
+
 func init() {
+	for name := range builtinProfiles {
+		printProfiles[name] = resolveBuiltinProfile(name)
+	}
+
 	// Build reverse mapping
 	profileNameToID = make(map[PrintProfile]int, len(profileIDs))
 	for id, name := range profileIDs {
@@ -367,7 +310,7 @@ func init() {
 func GetProfileByID(id int) (PrintProfile, error) {
 	profile, exists := profileIDs[id]
 	if !exists {
-		return "", fmt.Errorf("unknown profile ID: %d (valid IDs: 0-18)", id)
+		return "", fmt.Errorf("unknown profile ID: %d (valid IDs: 0-%d and custom profiles, see 'print list')", id, MaxBuiltinProfileID)
 	}
 	return profile, nil
 }
@@ -385,6 +328,8 @@ func GetProfileID(profile PrintProfile) int {
 type ProfileInfo struct {
 	ID          int
 	Name        PrintProfile
+	Custom      bool         // User-defined profile from the profiles file
+	Extends     PrintProfile // Base profile of a custom profile, if any
 	PaperSize   string
 	Tray        string
 	MediaType   string
@@ -393,15 +338,18 @@ type ProfileInfo struct {
 }
 
 // ListProfilesWithInfo returns detailed information about all profiles
-// sorted by ID for display purposes
+// sorted by ID for display purposes. Settings of profiles that extend
+// another profile are shown fully resolved.
 func ListProfilesWithInfo() []ProfileInfo {
-	infos := make([]ProfileInfo, 0, len(profileIDs))
+	ids := make([]int, 0, len(profileIDs))
+	for id := range profileIDs {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
 
-	for id := 0; id < len(profileIDs); id++ {
-		profile, err := GetProfileByID(id)
-		if err != nil {
-			continue
-		}
+	infos := make([]ProfileInfo, 0, len(ids))
+	for _, id := range ids {
+		profile := profileIDs[id]
 
 		opts, err := GetPrintOptions(profile)
 		if err != nil {
@@ -411,6 +359,8 @@ func ListProfilesWithInfo() []ProfileInfo {
 		info := ProfileInfo{
 			ID:          id,
 			Name:        profile,
+			Custom:      id > MaxBuiltinProfileID,
+			Extends:     profileExtends[profile],
 			PaperSize:   opts.PaperSize,
 			Tray:        opts.Tray,
 			MediaType:   opts.MediaType,
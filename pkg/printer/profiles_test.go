@@ -104,6 +104,96 @@ func TestGetPrintOptions(t *testing.T) {
 			expectedMedia: "stationery-coated",
 			expectedQual:  5,
 		},
+		{
+			name:          "Photo 4x6 borderless semi-gloss",
+			profile:       ProfilePhoto4x6BorderlessSemiGloss,
+			expectError:   false,
+			expectedSize:  "4x6.Borderless",
+			expectedTray:  "Photo",
+			expectedMedia: "photographic-semi-gloss",
+			expectedQual:  5,
+		},
+		{
+			name:          "Photo 5x7 borderless glossy",
+			profile:       ProfilePhoto5x7BorderlessGlossy,
+			expectError:   false,
+			expectedSize:  "5x7.Borderless",
+			expectedTray:  "Photo",
+			expectedMedia: "photographic-glossy",
+			expectedQual:  5,
+		},
+		{
+			name:          "Photo 5x7 borderless matte",
+			profile:       ProfilePhoto5x7BorderlessMatte,
+			expectError:   false,
+			expectedSize:  "5x7.Borderless",
+			expectedTray:  "Photo",
+			expectedMedia: "photographic-matte",
+			expectedQual:  5,
+		},
+		{
+			name:          "Photo 5x7 borderless semi-gloss",
+			profile:       ProfilePhoto5x7BorderlessSemiGloss,
+			expectError:   false,
+			expectedSize:  "5x7.Borderless",
+			expectedTray:  "Photo",
+			expectedMedia: "photographic-semi-gloss",
+			expectedQual:  5,
+		},
+		{
+			name:          "Photo A4 borderless glossy",
+			profile:       ProfilePhotoA4BorderlessGlossy,
+			expectError:   false,
+			expectedSize:  "A4.Borderless",
+			expectedTray:  "Auto",
+			expectedMedia: "photographic-glossy",
+			expectedQual:  5,
+		},
+		{
+			name:          "Photo A4 borderless matte",
+			profile:       ProfilePhotoA4BorderlessMatte,
+			expectError:   false,
+			expectedSize:  "A4.Borderless",
+			expectedTray:  "Auto",
+			expectedMedia: "photographic-matte",
+			expectedQual:  5,
+		},
+		{
+			name:          "Photo A3 borderless matte",
+			profile:       ProfilePhotoA3BorderlessMatte,
+			expectError:   false,
+			expectedSize:  "A3.Borderless",
+			expectedTray:  "Rear",
+			expectedMedia: "photographic-matte",
+			expectedQual:  5,
+		},
+		{
+			name:          "Photo A3 borderless semi-gloss",
+			profile:       ProfilePhotoA3BorderlessSemiGloss,
+			expectError:   false,
+			expectedSize:  "A3.Borderless",
+			expectedTray:  "Rear",
+			expectedMedia: "photographic-semi-gloss",
+			expectedQual:  5,
+		},
+		{
+			name:          "Photo A3+ borderless glossy",
+			profile:       ProfilePhotoA3PlusBorderlessGlossy,
+			expectError:   false,
+			expectedSize:  "13x19.Borderless",
+			expectedTray:  "Rear",
+			expectedMedia: "photographic-glossy",
+			expectedQual:  5,
+		},
+		{
+			name:          "Photo A3+ borderless semi-gloss",
+			profile:       ProfilePhotoA3PlusBorderlessSemiGloss,
+			expectError:   false,
+			expectedSize:  "13x19.Borderless",
+			expectedTray:  "Rear",
+			expectedMedia: "photographic-semi-gloss",
+			expectedQual:  5,
+		},
 		{
 			name:        "Unknown profile returns error",
 			profile:     "unknown-profile",
@@ -154,6 +244,68 @@ func TestGetPrintOptions(t *testing.T) {
 	}
 }
 
+func TestBuiltinProfilesExtend(t *testing.T) {
+	for name, profile := range builtinProfiles {
+		if !IsBuiltinProfile(name) {
+			t.Errorf("%s has no built-in profile ID", name)
+		}
+		if name == ProfileDefault {
+			continue
+		}
+
+		// Every variant is expressed as overrides of another built-in profile
+		if _, ok := builtinProfiles[profile.Extends]; !ok {
+			t.Errorf("%s extends %q, want a built-in profile", name, profile.Extends)
+		}
+		if profile.PrintOptions == MustGetPrintOptions(name) {
+			t.Errorf("%s repeats all settings of %s instead of overriding some", name, profile.Extends)
+		}
+	}
+}
+
+func TestBuiltinProfilesResolved(t *testing.T) {
+	// Resolved settings of every built-in profile and the profile it extends
+	tests := []struct {
+		profile PrintProfile
+		extends PrintProfile
+		want    PrintOptions
+	}{
+		{ProfileDefault, "", PrintOptions{PaperSize: "A4", Tray: "Main", MediaType: "stationery", Quality: 3, PageRange: "all", Copies: 1}},
+		{ProfilePhoto4x6BorderlessGlossy, ProfileDefault, PrintOptions{PaperSize: "4x6.Borderless", Tray: "Photo", MediaType: "photographic-glossy", Quality: 5, PageRange: "all", Copies: 1}},
+		{ProfilePhoto4x6BorderlessMatte, ProfilePhoto4x6BorderlessGlossy, PrintOptions{PaperSize: "4x6.Borderless", Tray: "Photo", MediaType: "photographic-matte", Quality: 5, PageRange: "all", Copies: 1}},
+		{ProfilePhoto4x6BorderlessSemiGloss, ProfilePhoto4x6BorderlessGlossy, PrintOptions{PaperSize: "4x6.Borderless", Tray: "Photo", MediaType: "photographic-semi-gloss", Quality: 5, PageRange: "all", Copies: 1}},
+		{ProfilePhoto5x7BorderlessGlossy, ProfileDefault, PrintOptions{PaperSize: "5x7.Borderless", Tray: "Photo", MediaType: "photographic-glossy", Quality: 5, PageRange: "all", Copies: 1}},
+		{ProfilePhoto5x7BorderlessMatte, ProfilePhoto5x7BorderlessGlossy, PrintOptions{PaperSize: "5x7.Borderless", Tray: "Photo", MediaType: "photographic-matte", Quality: 5, PageRange: "all", Copies: 1}},
+		{ProfilePhoto5x7BorderlessSemiGloss, ProfilePhoto5x7BorderlessGlossy, PrintOptions{PaperSize: "5x7.Borderless", Tray: "Photo", MediaType: "photographic-semi-gloss", Quality: 5, PageRange: "all", Copies: 1}},
+		{ProfilePhotoA4BorderlessGlossy, ProfileDefault, PrintOptions{PaperSize: "A4.Borderless", Tray: "Auto", MediaType: "photographic-glossy", Quality: 5, PageRange: "all", Copies: 1}},
+		{ProfilePhotoA4BorderlessMatte, ProfilePhotoA4BorderlessGlossy, PrintOptions{PaperSize: "A4.Borderless", Tray: "Auto", MediaType: "photographic-matte", Quality: 5, PageRange: "all", Copies: 1}},
+		{ProfilePhotoA4BorderlessSemiGloss, ProfilePhotoA4BorderlessGlossy, PrintOptions{PaperSize: "A4.Borderless", Tray: "Auto", MediaType: "photographic-semi-gloss", Quality: 5, PageRange: "all", Copies: 1}},
+		{ProfilePhotoA3BorderlessGlossy, ProfileDefault, PrintOptions{PaperSize: "A3.Borderless", Tray: "Rear", MediaType: "photographic-glossy", Quality: 5, PageRange: "all", Copies: 1}},
+		{ProfilePhotoA3BorderlessMatte, ProfilePhotoA3BorderlessGlossy, PrintOptions{PaperSize: "A3.Borderless", Tray: "Rear", MediaType: "photographic-matte", Quality: 5, PageRange: "all", Copies: 1}},
+		{ProfilePhotoA3BorderlessSemiGloss, ProfilePhotoA3BorderlessGlossy, PrintOptions{PaperSize: "A3.Borderless", Tray: "Rear", MediaType: "photographic-semi-gloss", Quality: 5, PageRange: "all", Copies: 1}},
+		{ProfilePhotoA3PlusBorderlessGlossy, ProfileDefault, PrintOptions{PaperSize: "13x19.Borderless", Tray: "Rear", MediaType: "photographic-glossy", Quality: 5, PageRange: "all", Copies: 1}},
+		{ProfilePhotoA3PlusBorderlessMatte, ProfilePhotoA3PlusBorderlessGlossy, PrintOptions{PaperSize: "13x19.Borderless", Tray: "Rear", MediaType: "photographic-matte", Quality: 5, PageRange: "all", Copies: 1}},
+		{ProfilePhotoA3PlusBorderlessSemiGloss, ProfilePhotoA3PlusBorderlessGlossy, PrintOptions{PaperSize: "13x19.Borderless", Tray: "Rear", MediaType: "photographic-semi-gloss", Quality: 5, PageRange: "all", Copies: 1}},
+		{ProfileDocumentDraft, ProfileDefault, PrintOptions{PaperSize: "A4", Tray: "Main", MediaType: "stationery", Quality: 3, PageRange: "all", Copies: 1}},
+		{ProfileDocumentNormal, ProfileDocumentDraft, PrintOptions{PaperSize: "A4", Tray: "Main", MediaType: "stationery", Quality: 4, PageRange: "all", Copies: 1}},
+		{ProfileDocumentBest, ProfileDocumentDraft, PrintOptions{PaperSize: "A4", Tray: "Main", MediaType: "stationery-coated", Quality: 5, PageRange: "all", Copies: 1}},
+	}
+
+	if len(tests) != len(builtinProfiles) {
+		t.Errorf("table covers %d profiles, want all %d built-in profiles", len(tests), len(builtinProfiles))
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.profile), func(t *testing.T) {
+			if got := builtinProfiles[tt.profile].Extends; got != tt.extends {
+				t.Errorf("Extends = %q, want %q", got, tt.extends)
+			}
+			if got := MustGetPrintOptions(tt.profile); got != tt.want {
+				t.Errorf("GetPrintOptions() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestMustGetPrintOptions(t *testing.T) {
 	// Should not panic for valid profile
 	opts := MustGetPrintOptions(ProfileDefault)
@@ -0,0 +1,304 @@
+package printer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ProfilesFileName is the name of the user profile file in ConfigDir
+const ProfilesFileName = "profiles.json"
+
+// UserProfile is a user-defined print profile with a stable numeric ID.
+// A profile that extends another one only stores the settings it
+// overrides; unset (zero) settings are inherited from the base profile.
+type UserProfile struct {
+	ID      int          `json:"id"`
+	Name    PrintProfile `json:"name"`
+	Extends PrintProfile `json:"extends,omitempty"`
+	PrintOptions
+}
+
+// ProfileStore holds the user-defined profiles saved in a JSON file.
+// Changes only take effect in GetPrintOptions and friends after Register.
+type ProfileStore struct {
+	Path     string        `json:"-"`
+	NextID   int           `json:"next_id"` // Never reused, so IDs stay stable after removals
+	Profiles []UserProfile `json:"profiles"`
+}
+
+// DefaultProfilesPath returns the path of profiles.json in ConfigDir
+func DefaultProfilesPath() (string, error) {
+	dir, err := ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, ProfilesFileName), nil
+}
+
+// LoadProfileStore reads user profiles from path. A missing file yields an
+// empty store that is created on Save. Profiles whose inheritance is broken
+// are kept, so they can be fixed or removed; see Problems.
+func LoadProfileStore(path string) (*ProfileStore, error) {
+	store := &ProfileStore{Path: path}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading profiles: %w", err)
+	}
+
+	if err := json.Unmarshal(data, store); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	if err := store.validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return store, nil
+}
+
+// Save writes the store to its path, creating the config directory if needed
+func (s *ProfileStore) Save() error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding profiles: %w", err)
+	}
+	return writeFileAtomic(s.Path, append(data, '\n'))
+}
+
+// Get returns the user profile with the given name
+func (s *ProfileStore) Get(name PrintProfile) (UserProfile, bool) {
+	i := s.index(name)
+	if i < 0 {
+		return UserProfile{}, false
+	}
+	return s.Profiles[i], true
+}
+
+// Add creates a user profile that extends base (or stands alone when base
+// is empty) with the given overrides, and assigns it the next free ID above
+// the built-in profiles
+func (s *ProfileStore) Add(name, base PrintProfile, overrides PrintOptions) (UserProfile, error) {
+	if err := validateProfileName(name); err != nil {
+		return UserProfile{}, err
+	}
+	if s.index(name) >= 0 {
+		return UserProfile{}, fmt.Errorf("profile %q already exists", name)
+	}
+
+	id := max(s.NextID, MaxBuiltinProfileID+1)
+	for _, p := range s.Profiles {
+		id = max(id, p.ID+1)
+	}
+
+	profile := UserProfile{ID: id, Name: name, Extends: base, PrintOptions: overrides}
+	s.Profiles = append(s.Profiles, profile)
+	if _, err := s.checkedResolve(name); err != nil {
+		s.Profiles = s.Profiles[:len(s.Profiles)-1]
+		return UserProfile{}, err
+	}
+
+	s.NextID = id + 1
+	return profile, nil
+}
+
+// Update replaces the base profile and overrides of an existing user
+// profile, keeping its ID
+func (s *ProfileStore) Update(name, base PrintProfile, overrides PrintOptions) error {
+	i := s.index(name)
+	if i < 0 {
+		return fmt.Errorf("unknown user profile: %s", name)
+	}
+
+	previous := s.Profiles[i]
+	s.Profiles[i].Extends = base
+	s.Profiles[i].PrintOptions = overrides
+	if _, err := s.checkedResolve(name); err != nil {
+		s.Profiles[i] = previous
+		return err
+	}
+	return nil
+}
+
+// Resolve returns the complete options of a profile, applying the overrides
+// of each user profile on top of the profile it extends. Profiles not in
+// the store are looked up with GetPrintOptions.
+func (s *ProfileStore) Resolve(name PrintProfile) (PrintOptions, error) {
+	return s.resolve(name, nil)
+}
+
+// resolve follows the extends chain, with seen holding the profiles
+// already visited to detect cycles
+func (s *ProfileStore) resolve(name PrintProfile, seen []PrintProfile) (PrintOptions, error) {
+	if slices.Contains(seen, name) {
+		chain := make([]string, 0, len(seen)+1)
+		for _, p := range append(seen, name) {
+			chain = append(chain, string(p))
+		}
+		return PrintOptions{}, fmt.Errorf("profile inheritance cycle: %s", strings.Join(chain, " -> "))
+	}
+
+	profile, ok := s.Get(name)
+	if !ok {
+		return GetPrintOptions(name)
+	}
+	if profile.Extends == "" {
+		return profile.PrintOptions, nil
+	}
+
+	base, err := s.resolve(profile.Extends, append(seen, name))
+	if err != nil {
+		return PrintOptions{}, err
+	}
+	return base.Overlay(profile.PrintOptions), nil
+}
+
+// checkedResolve resolves a profile and validates the resulting options
+func (s *ProfileStore) checkedResolve(name PrintProfile) (PrintOptions, error) {
+	opts, err := s.Resolve(name)
+	if err != nil {
+		return PrintOptions{}, fmt.Errorf("profile %q: %w", name, err)
+	}
+	if err := opts.Validate(nil); err != nil {
+		return PrintOptions{}, fmt.Errorf("profile %q: %w", name, err)
+	}
+	return opts, nil
+}
+
+// Remove deletes a user profile. Its ID is not reused. A profile that other
+// user profiles extend cannot be removed.
+func (s *ProfileStore) Remove(name PrintProfile) error {
+	i := s.index(name)
+	if i < 0 {
+		return fmt.Errorf("unknown user profile: %s", name)
+	}
+
+	var dependents []string
+	for _, p := range s.Profiles {
+		if p.Extends == name && p.Name != name {
+			dependents = append(dependents, string(p.Name))
+		}
+	}
+	if len(dependents) > 0 {
+		return fmt.Errorf("profile %q is extended by %s; remove them or change what they extend first",
+			name, strings.Join(dependents, ", "))
+	}
+
+	s.Profiles = slices.Delete(s.Profiles, i, i+1)
+	return nil
+}
+
+// Register makes the user profiles available by name and ID alongside the
+// built-in profiles, with inherited settings resolved. Profiles that cannot
+// be resolved are skipped; LoadProfileStore already rejects them.
+func (s *ProfileStore) Register() {
+	for _, p := range s.Profiles {
+		opts, err := s.Resolve(p.Name)
+		if err != nil {
+			continue
+		}
+		printProfiles[p.Name] = opts
+		profileIDs[p.ID] = p.Name
+		profileNameToID[p.Name] = p.ID
+		if p.Extends != "" {
+			profileExtends[p.Name] = p.Extends
+		}
+	}
+}
+
+// Export writes the named user profiles, or all of them when no names are
+// given, as JSON in the profiles file format
+func (s *ProfileStore) Export(w io.Writer, names ...PrintProfile) error {
+	export := ProfileStore{NextID: s.NextID, Profiles: s.Profiles}
+	if len(names) > 0 {
+		export.Profiles = nil
+		for _, name := range names {
+			profile, ok := s.Get(name)
+			if !ok {
+				return fmt.Errorf("unknown user profile: %s", name)
+			}
+			export.Profiles = append(export.Profiles, profile)
+		}
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(export); err != nil {
+		return fmt.Errorf("encoding profiles: %w", err)
+	}
+	return nil
+}
+
+// index returns the position of the named profile, or -1
+func (s *ProfileStore) index(name PrintProfile) int {
+	return slices.IndexFunc(s.Profiles, func(p UserProfile) bool {
+		return p.Name == name
+	})
+}
+
+// Problems returns an error for each profile that cannot be resolved, e.g.
+// because it extends an unknown profile or is part of a cycle. Register
+// skips these profiles.
+func (s *ProfileStore) Problems() []error {
+	var problems []error
+	for _, p := range s.Profiles {
+		if _, err := s.checkedResolve(p.Name); err != nil {
+			problems = append(problems, err)
+		}
+	}
+	return problems
+}
+
+// validate checks a loaded store for names or IDs that clash with each
+// other or with the built-in profiles. Broken inheritance only affects the
+// profiles involved and is reported by Problems instead.
+func (s *ProfileStore) validate() error {
+	names := make(map[PrintProfile]bool, len(s.Profiles))
+	ids := make(map[int]bool, len(s.Profiles))
+
+	for _, p := range s.Profiles {
+		if err := validateProfileName(p.Name); err != nil {
+			return err
+		}
+		if p.ID <= MaxBuiltinProfileID {
+			return fmt.Errorf("profile %q: ID %d is reserved for built-in profiles", p.Name, p.ID)
+		}
+		if names[p.Name] {
+			return fmt.Errorf("duplicate profile name %q", p.Name)
+		}
+		if ids[p.ID] {
+			return fmt.Errorf("duplicate profile ID %d", p.ID)
+		}
+		names[p.Name] = true
+		ids[p.ID] = true
+	}
+	return nil
+}
+
+// validateProfileName rejects names that are empty, contain whitespace,
+// could be mistaken for a numeric ID or shadow a built-in profile
+func validateProfileName(name PrintProfile) error {
+	switch {
+	case name == "":
+		return fmt.Errorf("profile name must not be empty")
+	case strings.ContainsFunc(string(name), unicode.IsSpace):
+		return fmt.Errorf("profile name %q must not contain whitespace", name)
+	case IsBuiltinProfile(name):
+		return fmt.Errorf("profile name %q is a built-in profile", name)
+	}
+
+	if _, err := strconv.Atoi(string(name)); err == nil {
+		return fmt.Errorf("profile name %q must not be a number", name)
+	}
+	return nil
+}
@@ -0,0 +1,430 @@
+package printer
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Test helper: remove user profiles registered by a test from the global registry
+func unregisterUserProfiles(t *testing.T, store *ProfileStore) {
+	t.Helper()
+	t.Cleanup(func() {
+		for _, p := range store.Profiles {
+			delete(printProfiles, p.Name)
+			delete(profileIDs, p.ID)
+			delete(profileNameToID, p.Name)
+			delete(profileExtends, p.Name)
+		}
+	})
+}
+
+func TestDefaultProfilesPath(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", "/tmp/xdg")
+
+	path, err := DefaultProfilesPath()
+	if err != nil {
+		t.Fatalf("DefaultProfilesPath() error = %v", err)
+	}
+	if want := filepath.Join("/tmp/xdg", "epson-printing", "profiles.json"); path != want {
+		t.Errorf("DefaultProfilesPath() = %s, want %s", path, want)
+	}
+}
+
+func TestProfileStore_SaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "epson-printing", ProfilesFileName)
+
+	store, err := LoadProfileStore(path)
+	if err != nil {
+		t.Fatalf("LoadProfileStore() on missing file error = %v", err)
+	}
+	if len(store.Profiles) != 0 {
+		t.Fatalf("expected empty store, got %d profiles", len(store.Profiles))
+	}
+
+	opts := PrintOptions{PaperSize: "A4", Tray: "Main", MediaType: "stationery-coated", Quality: 4, PageRange: "all", Copies: 2}
+	added, err := store.Add("my-calendar", "", opts)
+	if err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if added.ID != MaxBuiltinProfileID+1 {
+		t.Errorf("first user profile ID = %d, want %d", added.ID, MaxBuiltinProfileID+1)
+	}
+	if err := store.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	loaded, err := LoadProfileStore(path)
+	if err != nil {
+		t.Fatalf("LoadProfileStore() error = %v", err)
+	}
+	got, ok := loaded.Get("my-calendar")
+	if !ok {
+		t.Fatal("saved profile not found after reload")
+	}
+	if got.ID != added.ID || got.PrintOptions != opts {
+		t.Errorf("reloaded profile = %+v, want %+v", got, added)
+	}
+}
+
+func TestProfileStore_StableIDs(t *testing.T) {
+	store := &ProfileStore{Path: filepath.Join(t.TempDir(), ProfilesFileName)}
+	opts := PrintOptions{PaperSize: "A4"}
+
+	a, _ := store.Add("a", "", opts)
+	b, _ := store.Add("b", "", opts)
+	if err := store.Remove("a"); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	c, _ := store.Add("c", "", opts)
+
+	if a.ID != 19 || b.ID != 20 || c.ID != 21 {
+		t.Errorf("IDs = %d, %d, %d, want 19, 20, 21 (removed IDs are not reused)", a.ID, b.ID, c.ID)
+	}
+	if got, _ := store.Get("b"); got.ID != 20 {
+		t.Errorf("profile b changed ID to %d after removing a", got.ID)
+	}
+}
+
+func TestProfileStore_AddErrors(t *testing.T) {
+	store := &ProfileStore{}
+	if _, err := store.Add("existing", "", PrintOptions{PaperSize: "A4"}); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		profile PrintProfile
+		opts    PrintOptions
+		wantErr string
+	}{
+		{"empty name", "", PrintOptions{}, "must not be empty"},
+		{"whitespace", "my profile", PrintOptions{}, "must not contain whitespace"},
+		{"numeric", "42", PrintOptions{}, "must not be a number"},
+		{"built-in", ProfilePhotoA4BorderlessGlossy, PrintOptions{}, "is a built-in profile"},
+		{"duplicate", "existing", PrintOptions{}, "already exists"},
+		{"invalid paper", "typo", PrintOptions{PaperSize: "A4.Borderles"}, "did you mean"},
+		{"invalid pages", "pages", PrintOptions{PageRange: "5-1"}, "ends before it starts"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := store.Add(tt.profile, "", tt.opts)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Add(%q) error = %v, want it to contain %q", tt.profile, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestProfileStore_UpdateRemove(t *testing.T) {
+	store := &ProfileStore{}
+	added, _ := store.Add("proof", "", PrintOptions{PaperSize: "A4", Quality: 3})
+
+	if err := store.Update("proof", "", PrintOptions{PaperSize: "A3", Quality: 5}); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	got, _ := store.Get("proof")
+	if got.ID != added.ID || got.PaperSize != "A3" || got.Quality != 5 {
+		t.Errorf("updated profile = %+v", got)
+	}
+
+	if err := store.Update("missing", "", PrintOptions{}); err == nil {
+		t.Error("Update() expected error for unknown profile")
+	}
+	if err := store.Remove("proof"); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	if err := store.Remove("proof"); err == nil {
+		t.Error("Remove() expected error for removed profile")
+	}
+}
+
+func TestLoadProfileStore_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"malformed JSON", `{"profiles": [`, "parsing"},
+		{"reserved ID", `{"profiles": [{"id": 5, "name": "x"}]}`, "reserved for built-in profiles"},
+		{"built-in name", `{"profiles": [{"id": 19, "name": "default"}]}`, "is a built-in profile"},
+		{"duplicate name", `{"profiles": [{"id": 19, "name": "x"}, {"id": 20, "name": "x"}]}`, "duplicate profile name"},
+		{"duplicate ID", `{"profiles": [{"id": 19, "name": "x"}, {"id": 19, "name": "y"}]}`, "duplicate profile ID"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), ProfilesFileName)
+			if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			_, err := LoadProfileStore(path)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("LoadProfileStore() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestProfileStore_Register(t *testing.T) {
+	store := &ProfileStore{}
+	_, _ = store.Add("gap-a", "", PrintOptions{PaperSize: "A4"})
+	_, _ = store.Add("gap-b", "", PrintOptions{PaperSize: "5x7.Borderless", Quality: 5})
+	_ = store.Remove("gap-a") // Leaves a gap at ID 19
+	unregisterUserProfiles(t, store)
+
+	store.Register()
+
+	opts, err := GetPrintOptions("gap-b")
+	if err != nil || opts.PaperSize != "5x7.Borderless" {
+		t.Errorf("GetPrintOptions(gap-b) = %+v, %v", opts, err)
+	}
+	if name, err := GetProfileByID(20); err != nil || name != "gap-b" {
+		t.Errorf("GetProfileByID(20) = %s, %v", name, err)
+	}
+	if IsBuiltinProfile("gap-b") {
+		t.Error("user profile reported as built-in")
+	}
+
+	// ListProfilesWithInfo must include profiles after a gap in the IDs
+	infos := ListProfilesWithInfo()
+	last := infos[len(infos)-1]
+	if last.ID != 20 || last.Name != "gap-b" || !last.Custom {
+		t.Errorf("last listed profile = %+v, want custom gap-b with ID 20", last)
+	}
+	if infos[0].Custom {
+		t.Error("built-in profile marked as custom")
+	}
+}
+
+func TestProfileStore_Export(t *testing.T) {
+	store := &ProfileStore{}
+	_, _ = store.Add("one", "", PrintOptions{PaperSize: "A4"})
+	_, _ = store.Add("two", "", PrintOptions{PaperSize: "A3"})
+
+	var buf bytes.Buffer
+	if err := store.Export(&buf, "two"); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+
+	var exported ProfileStore
+	if err := json.Unmarshal(buf.Bytes(), &exported); err != nil {
+		t.Fatalf("Export() produced invalid JSON: %v", err)
+	}
+	if len(exported.Profiles) != 1 || exported.Profiles[0].Name != "two" || exported.Profiles[0].PaperSize != "A3" {
+		t.Errorf("exported profiles = %+v", exported.Profiles)
+	}
+	if !strings.Contains(buf.String(), `"paper_size": "A3"`) {
+		t.Errorf("expected flattened paper_size in export, got:\n%s", buf.String())
+	}
+
+	if err := store.Export(&buf, "missing"); err == nil {
+		t.Error("Export() expected error for unknown profile")
+	}
+}
+
+func TestProfileStore_Extends(t *testing.T) {
+	store := &ProfileStore{}
+
+	// Only the overridden settings are stored
+	added, err := store.Add("a4-glossy-rear", ProfilePhotoA4BorderlessGlossy, PrintOptions{Tray: "Rear"})
+	if err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if added.PaperSize != "" || added.Tray != "Rear" {
+		t.Errorf("stored overrides = %+v, want only Tray", added.PrintOptions)
+	}
+
+	// A user profile can extend another user profile
+	if _, err := store.Add("a4-matte-rear", "a4-glossy-rear", PrintOptions{MediaType: "photographic-matte"}); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+
+	got, err := store.Resolve("a4-matte-rear")
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	want := MustGetPrintOptions(ProfilePhotoA4BorderlessGlossy)
+	want.Tray = "Rear"
+	want.MediaType = "photographic-matte"
+	if got != want {
+		t.Errorf("Resolve() = %+v, want %+v", got, want)
+	}
+}
+
+func TestProfileStore_ExtendsErrors(t *testing.T) {
+	store := &ProfileStore{}
+	_, _ = store.Add("a", "default", PrintOptions{})
+	_, _ = store.Add("b", "a", PrintOptions{})
+
+	if _, err := store.Add("c", "missing", PrintOptions{}); err == nil || !strings.Contains(err.Error(), "unknown print profile: missing") {
+		t.Errorf("Add() with unknown base error = %v", err)
+	}
+	if _, ok := store.Get("c"); ok {
+		t.Error("failed Add() left the profile in the store")
+	}
+
+	err := store.Update("a", "b", PrintOptions{})
+	if err == nil || !strings.Contains(err.Error(), "profile inheritance cycle: a -> b -> a") {
+		t.Errorf("Update() creating a cycle error = %v", err)
+	}
+	if got, _ := store.Get("a"); got.Extends != "default" {
+		t.Errorf("failed Update() changed Extends to %q", got.Extends)
+	}
+
+	// Inherited settings are validated too
+	if _, err := store.Add("d", "a", PrintOptions{PageRange: "3-1"}); err == nil {
+		t.Error("Add() expected error for invalid resolved options")
+	}
+}
+
+func TestLoadProfileStore_Extends(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     string
+		wantProblem string
+	}{
+		{
+			name: "forward reference",
+			content: `{"profiles": [
+				{"id": 19, "name": "child", "extends": "parent", "copies": 3},
+				{"id": 20, "name": "parent", "extends": "document-best", "tray": "Rear"}]}`,
+		},
+		{
+			name: "cycle",
+			content: `{"profiles": [
+				{"id": 19, "name": "x", "extends": "y"},
+				{"id": 20, "name": "y", "extends": "x"}]}`,
+			wantProblem: "profile inheritance cycle: x -> y -> x",
+		},
+		{
+			name:        "self reference",
+			content:     `{"profiles": [{"id": 19, "name": "x", "extends": "x"}]}`,
+			wantProblem: "profile inheritance cycle: x -> x",
+		},
+		{
+			name:        "unknown base",
+			content:     `{"profiles": [{"id": 19, "name": "x", "extends": "nope"}]}`,
+			wantProblem: "unknown print profile: nope",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), ProfilesFileName)
+			if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			// Broken inheritance is reported per profile, the store still loads
+			store, err := LoadProfileStore(path)
+			if err != nil {
+				t.Fatalf("LoadProfileStore() error = %v", err)
+			}
+			problems := store.Problems()
+
+			if tt.wantProblem != "" {
+				if len(problems) == 0 || !strings.Contains(problems[0].Error(), tt.wantProblem) {
+					t.Errorf("Problems() = %v, want first to contain %q", problems, tt.wantProblem)
+				}
+				return
+			}
+			if len(problems) != 0 {
+				t.Fatalf("Problems() = %v, want none", problems)
+			}
+
+			got, _ := store.Resolve("child")
+			if got.Tray != "Rear" || got.Copies != 3 || got.MediaType != "stationery-coated" {
+				t.Errorf("Resolve(child) = %+v", got)
+			}
+		})
+	}
+}
+
+func TestLoadProfileStore_RegisterSkipsBroken(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ProfilesFileName)
+	content := `{"profiles": [
+		{"id": 19, "name": "broken", "extends": "nope"},
+		{"id": 20, "name": "fine", "extends": "document-best", "copies": 2}]}`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	store, err := LoadProfileStore(path)
+	if err != nil {
+		t.Fatalf("LoadProfileStore() error = %v", err)
+	}
+	store.Register()
+	unregisterUserProfiles(t, store)
+
+	if opts, err := GetPrintOptions("fine"); err != nil || opts.Copies != 2 {
+		t.Errorf("GetPrintOptions(fine) = %+v, %v", opts, err)
+	}
+	if _, err := GetPrintOptions("broken"); err == nil {
+		t.Error("GetPrintOptions(broken) expected error for skipped profile")
+	}
+
+	// The broken profile can still be removed
+	if err := store.Remove("broken"); err != nil {
+		t.Errorf("Remove(broken) error = %v", err)
+	}
+	if problems := store.Problems(); len(problems) != 0 {
+		t.Errorf("Problems() after Remove = %v, want none", problems)
+	}
+
+	// A profile extending itself is not its own dependent
+	store.Profiles = append(store.Profiles, UserProfile{ID: 21, Name: "self", Extends: "self"})
+	if err := store.Remove("self"); err != nil {
+		t.Errorf("Remove(self) error = %v", err)
+	}
+}
+
+func TestProfileStore_RemoveExtended(t *testing.T) {
+	store := &ProfileStore{}
+	_, _ = store.Add("base", ProfileDocumentBest, PrintOptions{Tray: "Rear"})
+	_, _ = store.Add("child-a", "base", PrintOptions{Copies: 2})
+	_, _ = store.Add("child-b", "base", PrintOptions{Copies: 3})
+
+	err := store.Remove("base")
+	if err == nil {
+		t.Fatal("Remove(base) expected error while other profiles extend it")
+	}
+	if !strings.Contains(err.Error(), "child-a, child-b") {
+		t.Errorf("Remove(base) error = %v, want it to name child-a, child-b", err)
+	}
+	if store.index("base") < 0 {
+		t.Error("Remove(base) removed the profile despite the error")
+	}
+
+	// Once the dependents are gone the base can be removed
+	for _, name := range []PrintProfile{"child-a", "child-b", "base"} {
+		if err := store.Remove(name); err != nil {
+			t.Errorf("Remove(%s) error = %v", name, err)
+		}
+	}
+	if len(store.Profiles) != 0 {
+		t.Errorf("Profiles = %v, want empty", store.Profiles)
+	}
+}
+
+func TestProfileStore_RegisterExtends(t *testing.T) {
+	store := &ProfileStore{}
+	_, _ = store.Add("proof-a3", ProfileDocumentBest, PrintOptions{PaperSize: "A3", Tray: "Rear"})
+	unregisterUserProfiles(t, store)
+
+	store.Register()
+
+	infos := ListProfilesWithInfo()
+	info := infos[len(infos)-1]
+	if info.Name != "proof-a3" || info.Extends != ProfileDocumentBest {
+		t.Fatalf("listed profile = %+v, want proof-a3 extending %s", info, ProfileDocumentBest)
+	}
+	if info.PaperSize != "A3" || info.MediaType != "stationery-coated" || info.Quality != 5 {
+		t.Errorf("listed profile not resolved: %+v", info)
+	}
+}
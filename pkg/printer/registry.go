@@ -0,0 +1,141 @@
+package printer
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// PrinterEntry is a named printer in the registry saved in config.json
+type PrinterEntry struct {
+	Name     string     `json:"name"`
+	URI      string     `json:"uri"`
+	Profile  string     `json:"profile,omitempty"`  // Default print profile, name or ID
+	Model    string     `json:"model,omitempty"`    // Built-in model name, e.g. "EPSON ET-2850"
+	Username string     `json:"username,omitempty"` // Sent as requesting-user-name and for HTTP basic auth
+	Password string     `json:"password,omitempty"` // HTTP basic auth password, e.g. for a CUPS server; saved in cleartext
+	TLS      TLSOptions `json:"tls,omitzero"`
+}
+
+// NewClientFor returns a Client for a registry entry, with its credentials
+// and TLS settings applied
+func NewClientFor(entry PrinterEntry) (*Client, error) {
+	client := NewClient(entry.URI)
+	if entry.Username != "" {
+		client.UserName = entry.Username
+	}
+	client.Password = entry.Password
+
+	if entry.TLS != (TLSOptions{}) {
+		httpClient, err := NewHTTPClient(entry.TLS)
+		if err != nil {
+			return nil, fmt.Errorf("printer %s: %w", entry.Name, err)
+		}
+		client.HTTPClient = httpClient
+	}
+	return client, nil
+}
+
+// Printer returns the registry entry with the given name
+func (c *Config) Printer(name string) (PrinterEntry, error) {
+	i := c.printerIndex(name)
+	if i < 0 {
+		return PrinterEntry{}, fmt.Errorf("unknown printer: %s", name)
+	}
+	return c.Printers[i], nil
+}
+
+// DefaultPrinterEntry returns the default printer, if one is set
+func (c *Config) DefaultPrinterEntry() (PrinterEntry, bool) {
+	if c.DefaultPrinter == "" {
+		return PrinterEntry{}, false
+	}
+	entry, err := c.Printer(c.DefaultPrinter)
+	return entry, err == nil
+}
+
+// AddPrinter adds a printer to the registry. The first printer added
+// becomes the default.
+func (c *Config) AddPrinter(entry PrinterEntry) error {
+	if err := validatePrinterEntry(entry); err != nil {
+		return err
+	}
+	if c.printerIndex(entry.Name) >= 0 {
+		return fmt.Errorf("printer %q already exists", entry.Name)
+	}
+
+	c.Printers = append(c.Printers, entry)
+	if c.DefaultPrinter == "" {
+		c.DefaultPrinter = entry.Name
+	}
+	return nil
+}
+
+// RemovePrinter deletes a printer from the registry, and clears the default
+// printer if it was the default
+func (c *Config) RemovePrinter(name string) error {
+	i := c.printerIndex(name)
+	if i < 0 {
+		return fmt.Errorf("unknown printer: %s", name)
+	}
+
+	if strings.EqualFold(c.DefaultPrinter, c.Printers[i].Name) {
+		c.DefaultPrinter = ""
+	}
+	c.Printers = slices.Delete(c.Printers, i, i+1)
+	return nil
+}
+
+// SetDefaultPrinter makes the named printer the default
+func (c *Config) SetDefaultPrinter(name string) error {
+	entry, err := c.Printer(name)
+	if err != nil {
+		return err
+	}
+	c.DefaultPrinter = entry.Name
+	return nil
+}
+
+// printerIndex returns the position of the named printer, or -1.
+// Names are matched case-insensitively.
+func (c *Config) printerIndex(name string) int {
+	return slices.IndexFunc(c.Printers, func(p PrinterEntry) bool {
+		return strings.EqualFold(p.Name, name)
+	})
+}
+
+// validatePrinters checks a loaded registry for invalid entries, duplicate
+// names and a default printer that does not exist
+func (c *Config) validatePrinters() error {
+	for i, p := range c.Printers {
+		if err := validatePrinterEntry(p); err != nil {
+			return err
+		}
+		if c.printerIndex(p.Name) != i {
+			return fmt.Errorf("duplicate printer name %q", p.Name)
+		}
+	}
+	if c.DefaultPrinter != "" && c.printerIndex(c.DefaultPrinter) < 0 {
+		return fmt.Errorf("default printer %q is not in the printer list", c.DefaultPrinter)
+	}
+	return nil
+}
+
+// validatePrinterEntry checks the name and URI of a registry entry
+func validatePrinterEntry(entry PrinterEntry) error {
+	if entry.Name == "" || strings.ContainsFunc(entry.Name, func(r rune) bool { return r == ' ' || r == '\t' }) {
+		return fmt.Errorf("invalid printer name %q (must be non-empty without spaces)", entry.Name)
+	}
+	if entry.URI == "" {
+		return fmt.Errorf("printer %s: missing URI", entry.Name)
+	}
+	if _, err := transportURL(entry.URI); err != nil {
+		return fmt.Errorf("printer %s: %w", entry.Name, err)
+	}
+	if entry.Model != "" {
+		if _, err := LookupModel(entry.Model); err != nil {
+			return fmt.Errorf("printer %s: %w", entry.Name, err)
+		}
+	}
+	return nil
+}
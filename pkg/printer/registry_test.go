@@ -0,0 +1,155 @@
+package printer
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Eric-Eklund/epson-printing/pkg/printer/ipptest"
+)
+
+func TestConfig_Printers(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ConfigFileName)
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+
+	photo := PrinterEntry{Name: "photo", URI: "ipps://EPSON6A3B2C.local/ipp/print", Profile: "photo-a3plus-borderless-matte",
+		TLS: TLSOptions{InsecureSkipVerify: true}}
+	office := PrinterEntry{Name: "office", URI: "http://print-server:631/printers/ET-2850", Username: "anna", Password: "secret"}
+
+	if err := cfg.AddPrinter(photo); err != nil {
+		t.Fatalf("AddPrinter(photo) error = %v", err)
+	}
+	if err := cfg.AddPrinter(office); err != nil {
+		t.Fatalf("AddPrinter(office) error = %v", err)
+	}
+	if cfg.DefaultPrinter != "photo" {
+		t.Errorf("DefaultPrinter = %q, want first printer added", cfg.DefaultPrinter)
+	}
+
+	for _, entry := range []PrinterEntry{
+		{Name: "Office", URI: "ipp://other.local/ipp/print"}, // Names are case-insensitive
+		{Name: "my printer", URI: "ipp://other.local/ipp/print"},
+		{Name: "nouri"},
+		{Name: "badscheme", URI: "lpd://other.local/queue"},
+	} {
+		if err := cfg.AddPrinter(entry); err == nil {
+			t.Errorf("AddPrinter(%+v) expected error", entry)
+		}
+	}
+
+	if err := cfg.SetDefaultPrinter("OFFICE"); err != nil {
+		t.Fatalf("SetDefaultPrinter() error = %v", err)
+	}
+	if err := cfg.SetDefaultPrinter("missing"); err == nil {
+		t.Error("SetDefaultPrinter(missing) expected error")
+	}
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	// Credentials are saved, so the file must stay private
+	if stat, err := os.Stat(path); err != nil {
+		t.Fatal(err)
+	} else if mode := stat.Mode().Perm(); mode&0o077 != 0 {
+		t.Errorf("config file mode = %v, want no group or other access", mode)
+	}
+
+	loaded, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	entry, ok := loaded.DefaultPrinterEntry()
+	if !ok || entry != office {
+		t.Errorf("DefaultPrinterEntry() = %+v, %v; want %+v", entry, ok, office)
+	}
+	if entry, err := loaded.Printer("photo"); err != nil || entry != photo {
+		t.Errorf("Printer(photo) = %+v, %v; want %+v", entry, err, photo)
+	}
+
+	if err := loaded.RemovePrinter("office"); err != nil {
+		t.Fatalf("RemovePrinter() error = %v", err)
+	}
+	if loaded.DefaultPrinter != "" {
+		t.Errorf("DefaultPrinter = %q after removing it, want empty", loaded.DefaultPrinter)
+	}
+	if _, ok := loaded.DefaultPrinterEntry(); ok {
+		t.Error("DefaultPrinterEntry() found a printer after removing the default")
+	}
+	if err := loaded.RemovePrinter("office"); err == nil {
+		t.Error("RemovePrinter() of a removed printer expected error")
+	}
+}
+
+func TestLoadConfig_InvalidPrinters(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+	}{
+		{"duplicate name", `{"printers": [{"name": "a", "uri": "ipp://a/ipp/print"}, {"name": "A", "uri": "ipp://b/ipp/print"}]}`},
+		{"unknown default", `{"printers": [{"name": "a", "uri": "ipp://a/ipp/print"}], "default_printer": "b"}`},
+		{"missing URI", `{"printers": [{"name": "a"}]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), ConfigFileName)
+			if err := os.WriteFile(path, []byte(tt.json), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := LoadConfig(path); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestNewClientFor(t *testing.T) {
+	// CUPS-style basic auth in front of the emulated printer
+	p := ipptest.NewPrinter()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, password, ok := r.BasicAuth(); !ok || user != "anna" || password != "secret" {
+			w.Header().Set("WWW-Authenticate", `Basic realm="CUPS"`)
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		p.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	entry := PrinterEntry{Name: "office", URI: server.URL + "/ipp/print", Username: "anna", Password: "secret"}
+	client, err := NewClientFor(entry)
+	if err != nil {
+		t.Fatalf("NewClientFor() error = %v", err)
+	}
+	if client.UserName != "anna" {
+		t.Errorf("UserName = %q, want anna", client.UserName)
+	}
+
+	info, err := client.Info(context.Background())
+	if err != nil {
+		t.Fatalf("Info() error = %v", err)
+	}
+	if info.Model != p.MakeAndModel {
+		t.Errorf("Model = %q, want %q", info.Model, p.MakeAndModel)
+	}
+
+	if _, err := GetPrinterInfoFor(entry); err != nil {
+		t.Errorf("GetPrinterInfoFor() error = %v", err)
+	}
+
+	entry.Password = "wrong"
+	if _, err := GetPrinterInfoFor(entry); !IsNotAuthorized(err) {
+		t.Errorf("GetPrinterInfoFor() with wrong password error = %v, want not authorized", err)
+	}
+
+	entry.TLS = TLSOptions{CAFile: filepath.Join(t.TempDir(), "missing.pem")}
+	if _, err := NewClientFor(entry); err == nil {
+		t.Error("NewClientFor() with missing CA file expected error")
+	}
+}
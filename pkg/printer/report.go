@@ -1,10 +1,12 @@
 package printer
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
 	"runtime"
+	"strings"
 	"time"
 
 	"github.com/go-pdf/fpdf"
@@ -12,6 +14,8 @@ import (
 
 // GenerateStatusReport creates a PDF report with printer information
 func GenerateStatusReport(info *Info, printerURI, outputPath string) error {
+	model := ModelFor(info.Model)
+
 	pdf := fpdf.New("P", "mm", "A4", "")
 	pdf.AddPage()
 
@@ -19,7 +23,7 @@ func GenerateStatusReport(info *Info, printerURI, outputPath string) error {
 	pdf.SetFillColor(41, 128, 185)  // Blue background
 	pdf.SetTextColor(255, 255, 255) // White text
 	pdf.SetFont("Helvetica", "B", 18)
-	pdf.CellFormat(0, 12, "EPSON ET-8550 STATUS REPORT", "1", 1, "C", true, 0, "")
+	pdf.CellFormat(0, 12, model.reportTitle(info.Model), "1", 1, "C", true, 0, "")
 	pdf.SetTextColor(0, 0, 0) // Reset to black
 
 	// Date/Time with light background
@@ -41,7 +45,7 @@ func GenerateStatusReport(info *Info, printerURI, outputPath string) error {
 	pdf.Ln(2)
 
 	// Ink Levels Section with graphical bars
-	drawSectionHeader(pdf, "INK LEVELS (6-COLOR SYSTEM)", 46, 204, 113)
+	drawSectionHeader(pdf, model.inkTitle(len(info.InkLevels)), 46, 204, 113)
 	pdf.Ln(1)
 
 	for _, ink := range info.InkLevels {
@@ -49,6 +53,24 @@ func GenerateStatusReport(info *Info, printerURI, outputPath string) error {
 	}
 	pdf.Ln(2)
 
+	// Maintenance box section, when the printer reports one
+	if m := info.Maintenance; m.Status != "" && m.Status != MaintenanceUnknown {
+		drawSectionHeader(pdf, "MAINTENANCE BOX", 230, 126, 34)
+		pdf.Ln(1)
+		for _, box := range m.Boxes {
+			drawWasteLevelBar(pdf, box)
+		}
+		pdf.SetFont("Helvetica", "", 11)
+		drawInfoRow(pdf, "Status:", m.Status)
+		if len(m.Reasons) > 0 {
+			drawInfoRow(pdf, "Reason:", strings.Join(m.Reasons, ", "))
+		}
+		if advice := m.Advice(); advice != "" {
+			drawInfoRow(pdf, "Action:", advice)
+		}
+		pdf.Ln(2)
+	}
+
 	// Program Information Section
 	drawSectionHeader(pdf, "PROGRAM INFORMATION", 155, 89, 182)
 	pdf.SetFont("Helvetica", "", 10)
@@ -112,7 +134,40 @@ func drawInfoRow(pdf *fpdf.Fpdf, key, value string) {
 
 // drawInkLevelBar draws a graphical ink level bar
 func drawInkLevelBar(pdf *fpdf.Fpdf, name string, level int) {
-	// Ink name
+	// Color based on level: red if low, yellow if medium, green if high
+	var r, g, b int
+	if level < 20 {
+		r, g, b = 231, 76, 60 // Red
+	} else if level < 50 {
+		r, g, b = 241, 196, 15 // Yellow
+	} else {
+		r, g, b = 46, 204, 113 // Green
+	}
+	drawLevelBar(pdf, name, level, fmt.Sprintf("%3d%%", level), r, g, b)
+}
+
+// drawWasteLevelBar draws how full a maintenance box is: green while there
+// is room, yellow from 75%, red once the printer reports it almost full
+func drawWasteLevelBar(pdf *fpdf.Fpdf, box MaintenanceBox) {
+	if box.Level < 0 {
+		drawLevelBar(pdf, box.Name, 0, "unknown", 0, 0, 0)
+		return
+	}
+
+	var r, g, b int
+	if box.AlmostFull() || box.Level >= 100 {
+		r, g, b = 231, 76, 60 // Red
+	} else if box.Level >= 75 {
+		r, g, b = 241, 196, 15 // Yellow
+	} else {
+		r, g, b = 46, 204, 113 // Green
+	}
+	drawLevelBar(pdf, box.Name, box.Level, fmt.Sprintf("%3d%% full", box.Level), r, g, b)
+}
+
+// drawLevelBar draws a named bar filled to level percent in the given color
+func drawLevelBar(pdf *fpdf.Fpdf, name string, level int, label string, r, g, b int) {
+	// Marker name
 	pdf.SetFont("Helvetica", "B", 10)
 	pdf.CellFormat(40, 8, "  "+name, "", 0, "L", false, 0, "")
 
@@ -126,17 +181,10 @@ func drawInkLevelBar(pdf *fpdf.Fpdf, name string, level int) {
 	pdf.SetFillColor(220, 220, 220)
 	pdf.Rect(x, y+1, barWidth, barHeight, "F")
 
-	// Draw filled portion (green gradient based on level)
+	// Draw filled portion
 	if level > 0 {
-		fillWidth := (float64(level) / 100.0) * barWidth
-		// Color based on level: red if low, yellow if medium, green if high
-		if level < 20 {
-			pdf.SetFillColor(231, 76, 60) // Red
-		} else if level < 50 {
-			pdf.SetFillColor(241, 196, 15) // Yellow
-		} else {
-			pdf.SetFillColor(46, 204, 113) // Green
-		}
+		fillWidth := (float64(min(level, 100)) / 100.0) * barWidth
+		pdf.SetFillColor(r, g, b)
 		pdf.Rect(x, y+1, fillWidth, barHeight, "F")
 	}
 
@@ -147,20 +195,25 @@ func drawInkLevelBar(pdf *fpdf.Fpdf, name string, level int) {
 	// Percentage text
 	pdf.SetX(x + barWidth + 3)
 	pdf.SetFont("Helvetica", "", 10)
-	pdf.CellFormat(20, 8, fmt.Sprintf("%3d%%", level), "", 1, "R", false, 0, "")
+	pdf.CellFormat(20, 8, label, "", 1, "R", false, 0, "")
 }
 
 // PrintStatusReport generates and prints a status report
 func PrintStatusReport(printerURI string) (string, int, error) {
+	return NewClient(printerURI).PrintStatusReport(context.Background())
+}
+
+// PrintStatusReport generates and prints a status report
+func (c *Client) PrintStatusReport(ctx context.Context) (string, int, error) {
 	// Get printer information
-	info, err := GetPrinterInfo(printerURI)
+	info, err := c.Info(ctx)
 	if err != nil {
 		return "", 0, fmt.Errorf("getting printer info: %w", err)
 	}
 
 	// Generate PDF
 	pdfPath := fmt.Sprintf("printer-status-%s.pdf", time.Now().Format("20060102-150405"))
-	err = GenerateStatusReport(info, printerURI, pdfPath)
+	err = GenerateStatusReport(info, c.URI, pdfPath)
 	if err != nil {
 		return "", 0, fmt.Errorf("generating PDF: %w", err)
 	}
@@ -168,7 +221,7 @@ func PrintStatusReport(printerURI string) (string, int, error) {
 	// Print the PDF using document-normal profile (A4, quality 4)
 	opts := MustGetPrintOptions(ProfileDocumentNormal)
 
-	jobID, err := PrintPDF(printerURI, pdfPath, opts)
+	result, err := c.PrintPDF(ctx, pdfPath, opts)
 	if err != nil {
 		// Clean up PDF on print error
 		if removeErr := os.Remove(pdfPath); removeErr != nil {
@@ -177,5 +230,5 @@ func PrintStatusReport(printerURI string) (string, int, error) {
 		return "", 0, fmt.Errorf("printing PDF: %w", err)
 	}
 
-	return pdfPath, jobID, nil
+	return pdfPath, result.JobID, nil
 }
@@ -57,6 +57,24 @@ func TestGenerateStatusReport(t *testing.T) {
 			printerURI: "http://localhost:631/printers/EPSON_ET-8550_Series",
 			wantError:  false,
 		},
+		{
+			name: "printer with a full maintenance box",
+			info: &Info{
+				Name:         "EPSON ET-8550",
+				Model:        "EPSON ET-8550 Series",
+				State:        "Stopped",
+				StateReasons: "marker-waste-full-error",
+				InkLevels: []InkLevel{
+					{Name: "Black", Level: 50, Color: "#000000"},
+				},
+				Maintenance: newMaintenance(
+					[]MaintenanceBox{{Name: "Maintenance Box", Level: 100, HighLevel: 90}},
+					[]string{"marker-waste-full-error"},
+				),
+			},
+			printerURI: "http://localhost:631/printers/EPSON_ET-8550_Series",
+			wantError:  false,
+		},
 		{
 			name: "printer with state reasons",
 			info: &Info{
@@ -0,0 +1,104 @@
+package printer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/OpenPrinting/goipp"
+)
+
+// JobResult describes a print job accepted by the printer
+type JobResult struct {
+	JobID         int                    `json:"job_id"`
+	JobURI        string                 `json:"job_uri,omitempty"`
+	JobState      JobState               `json:"job_state"`
+	StatusMessage string                 `json:"status_message,omitempty"`
+	Substituted   []SubstitutedAttribute `json:"substituted,omitempty"`
+}
+
+// SubstitutedAttribute is a job setting the printer ignored or replaced
+// with its own default, as reported in the unsupported attributes group of
+// a successful-ok-ignored-or-substituted-attributes response
+type SubstitutedAttribute struct {
+	Name  string `json:"name"`            // e.g. "media-col.media-type"
+	Value string `json:"value,omitempty"` // Rejected value; empty when the attribute itself is not supported
+}
+
+// String returns the attribute as name=value, or the name alone when the
+// attribute is not supported at all
+func (a SubstitutedAttribute) String() string {
+	if a.Value == "" {
+		return a.Name + " (not supported)"
+	}
+	return a.Name + "=" + a.Value
+}
+
+// parseJobResult builds a JobResult from a Print-Job response
+func parseJobResult(msg *goipp.Message) *JobResult {
+	result := &JobResult{
+		JobID:         getInt(msg.Job, "job-id"),
+		JobURI:        getString(msg.Job, "job-uri"),
+		JobState:      JobState(getInt(msg.Job, "job-state")),
+		StatusMessage: getString(msg.Operation, "status-message"),
+	}
+
+	for _, attr := range msg.Unsupported {
+		result.Substituted = appendSubstituted(result.Substituted, attr.Name, attr.Values)
+	}
+
+	return result
+}
+
+// appendSubstituted adds the values of an unsupported attribute. Members of
+// collections such as media-col are listed individually with dotted names,
+// so a rejected media-type is reported as media-col.media-type.
+func appendSubstituted(list []SubstitutedAttribute, name string, values goipp.Values) []SubstitutedAttribute {
+	var plain []string
+	for _, v := range values {
+		if col, ok := v.V.(goipp.Collection); ok {
+			for _, member := range col {
+				list = appendSubstituted(list, name+"."+member.Name, member.Values)
+			}
+			continue
+		}
+		// Out-of-band values such as 'unsupported' carry no value
+		if v.T.Type() == goipp.TypeVoid {
+			continue
+		}
+		plain = append(plain, v.V.String())
+	}
+
+	if len(plain) > 0 || !hasCollection(values) {
+		list = append(list, SubstitutedAttribute{Name: name, Value: strings.Join(plain, ",")})
+	}
+	return list
+}
+
+// hasCollection reports whether any of the values is a collection
+func hasCollection(values goipp.Values) bool {
+	for _, v := range values {
+		if v.T == goipp.TagBeginCollection {
+			return true
+		}
+	}
+	return false
+}
+
+// Warning returns a message listing the settings the printer ignored or
+// substituted, or "" when it accepted all of them
+func (r *JobResult) Warning() string {
+	if r == nil || len(r.Substituted) == 0 {
+		return ""
+	}
+
+	names := make([]string, 0, len(r.Substituted))
+	for _, a := range r.Substituted {
+		names = append(names, a.String())
+	}
+	msg := fmt.Sprintf("printer ignored or substituted %d setting(s): %s",
+		len(r.Substituted), strings.Join(names, ", "))
+	if r.StatusMessage != "" {
+		msg += fmt.Sprintf(" (%s)", r.StatusMessage)
+	}
+	return msg
+}
@@ -0,0 +1,108 @@
+package printer
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/OpenPrinting/goipp"
+)
+
+func TestClient_Print_Substituted(t *testing.T) {
+	server := newTestIPPServer(t, func(r *http.Request, req *goipp.Message) *goipp.Message {
+		_, _ = io.Copy(io.Discard, r.Body)
+
+		resp := goipp.NewResponse(goipp.DefaultVersion, goipp.StatusOkIgnoredOrSubstituted, req.RequestID)
+		resp.Operation.Add(goipp.MakeAttr("status-message",
+			goipp.TagText, goipp.String("successful-ok-ignored-or-substituted-attributes")))
+		resp.Job.Add(goipp.MakeAttr("job-id", goipp.TagInteger, goipp.Integer(7)))
+		resp.Job.Add(goipp.MakeAttr("job-uri", goipp.TagURI, goipp.String("ipp://printer/jobs/7")))
+		resp.Job.Add(goipp.MakeAttr("job-state", goipp.TagEnum, goipp.Integer(JobPending)))
+
+		var mediaCol goipp.Collection
+		mediaCol.Add(goipp.MakeAttr("media-type",
+			goipp.TagKeyword, goipp.String("photographic-glossy")))
+		resp.Unsupported.Add(goipp.MakeAttribute("media-col", goipp.TagBeginCollection, mediaCol))
+		resp.Unsupported.Add(goipp.MakeAttr("print-quality", goipp.TagEnum, goipp.Integer(5)))
+		resp.Unsupported.Add(goipp.MakeAttr("print-scaling", goipp.TagUnsupportedValue, goipp.Void{}))
+		return resp
+	})
+
+	opts := MustGetPrintOptions(ProfilePhoto4x6BorderlessGlossy)
+	result, err := NewClient(server.URL).Print(context.Background(), "photo.pdf", FormatPDF, strings.NewReader("%PDF"), opts)
+	if err != nil {
+		t.Fatalf("Print() error = %v", err)
+	}
+
+	if result.JobID != 7 || result.JobURI != "ipp://printer/jobs/7" || result.JobState != JobPending {
+		t.Errorf("result = %+v, want job 7 pending at ipp://printer/jobs/7", result)
+	}
+	want := []SubstitutedAttribute{
+		{Name: "media-col.media-type", Value: "photographic-glossy"},
+		{Name: "print-quality", Value: "5"},
+		{Name: "print-scaling"},
+	}
+	if !reflect.DeepEqual(result.Substituted, want) {
+		t.Errorf("Substituted = %v, want %v", result.Substituted, want)
+	}
+
+	warning := result.Warning()
+	for _, s := range []string{"3 setting(s)", "media-col.media-type=photographic-glossy", "print-scaling (not supported)"} {
+		if !strings.Contains(warning, s) {
+			t.Errorf("Warning() = %q, missing %q", warning, s)
+		}
+	}
+}
+
+func TestClient_Print_ErrorResult(t *testing.T) {
+	server := newTestIPPServer(t, func(r *http.Request, req *goipp.Message) *goipp.Message {
+		_, _ = io.Copy(io.Discard, r.Body)
+
+		resp := goipp.NewResponse(goipp.DefaultVersion, goipp.StatusErrorAttributesOrValues, req.RequestID)
+		resp.Operation.Add(goipp.MakeAttr("status-message",
+			goipp.TagText, goipp.String("unsupported media")))
+		resp.Unsupported.Add(goipp.MakeAttr("media", goipp.TagKeyword, goipp.String("custom")))
+		return resp
+	})
+
+	result, err := NewClient(server.URL).Print(context.Background(), "doc.pdf", FormatPDF, strings.NewReader("%PDF"), DefaultPrintOptions())
+	if err == nil {
+		t.Fatal("Print() expected error for rejected job")
+	}
+	if result == nil {
+		t.Fatal("Print() expected result alongside printer error")
+	}
+	if result.StatusMessage != "unsupported media" {
+		t.Errorf("StatusMessage = %q, want %q", result.StatusMessage, "unsupported media")
+	}
+	if len(result.Substituted) != 1 || result.Substituted[0].Name != "media" {
+		t.Errorf("Substituted = %v, want media", result.Substituted)
+	}
+}
+
+func TestJobResult_Warning(t *testing.T) {
+	tests := []struct {
+		name   string
+		result *JobResult
+		want   string
+	}{
+		{"nil result", nil, ""},
+		{"all accepted", &JobResult{JobID: 1}, ""},
+		{
+			name:   "with message",
+			result: &JobResult{Substituted: []SubstitutedAttribute{{Name: "copies", Value: "2"}}, StatusMessage: "ignored"},
+			want:   "printer ignored or substituted 1 setting(s): copies=2 (ignored)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.result.Warning(); got != tt.want {
+				t.Errorf("Warning() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package printer
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"time"
+
+	"github.com/OpenPrinting/goipp"
+)
+
+// Default backoff delays used when a RetryPolicy leaves them unset
+const (
+	DefaultRetryInitialDelay = time.Second
+	DefaultRetryMaxDelay     = 30 * time.Second
+)
+
+// idempotentOps are the operations that can be repeated without side
+// effects, so they are retried even when the printer received the request
+var idempotentOps = []goipp.Op{
+	goipp.OpGetPrinterAttributes,
+	goipp.OpGetJobAttributes,
+	goipp.OpGetJobs,
+	goipp.OpValidateJob,
+}
+
+// RetryPolicy controls how requests are repeated after transient failures,
+// e.g. while the printer wakes from sleep or reports server-error-busy.
+// Delays grow exponentially from InitialDelay up to MaxDelay, with random
+// jitter so several clients do not retry in lockstep.
+//
+// Queries such as Get-Printer-Attributes and Get-Jobs are retried on
+// connection errors and busy or temporary errors. Other operations,
+// including Print-Job, are only retried when the connection could not be
+// established and no document data was read, so the printer provably never
+// saw the request and a job is never printed twice.
+type RetryPolicy struct {
+	MaxRetries   int           // Retries after the first attempt; 0 disables retrying
+	InitialDelay time.Duration // Delay before the first retry (default 1s)
+	MaxDelay     time.Duration // Upper bound for the delay (default 30s)
+
+	// OnRetry is called before waiting for each retry, e.g. for logging
+	OnRetry func(attempt int, delay time.Duration, err error)
+}
+
+// delay returns the backoff before the given retry (1 for the first)
+func (p RetryPolicy) delay(retry int) time.Duration {
+	initial := p.InitialDelay
+	if initial <= 0 {
+		initial = DefaultRetryInitialDelay
+	}
+	limit := p.MaxDelay
+	if limit <= 0 {
+		limit = DefaultRetryMaxDelay
+	}
+
+	d := initial
+	for i := 1; i < retry && d < limit; i++ {
+		d *= 2
+	}
+	d = min(d, limit)
+
+	// Equal jitter: half the delay fixed, half random
+	return d/2 + rand.N(d/2+1)
+}
+
+// retryable reports whether a failed request may be sent again. sent is the
+// number of document bytes read before the failure.
+func retryable(op goipp.Op, err error, sent int64) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if isDialError(err) && sent == 0 {
+		return true
+	}
+
+	for _, idempotent := range idempotentOps {
+		if op == idempotent {
+			return IsPrinterBusy(err) || isDialError(err)
+		}
+	}
+	return false
+}
+
+// isDialError reports whether err occurred while connecting, before any
+// data was sent, e.g. connection refused while the printer wakes up
+func isDialError(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// wait sleeps for d or until ctx is done
+func wait(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// countingReader counts the bytes read from a document
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,232 @@
+package printer
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/OpenPrinting/goipp"
+)
+
+// Test helper: a retry policy with delays short enough for tests
+func testRetryPolicy(retries int) RetryPolicy {
+	return RetryPolicy{MaxRetries: retries, InitialDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond}
+}
+
+// Test helper: start an IPP server that answers the first failures requests
+// with status and later ones with the mock printer attributes
+func newFlakyServer(t *testing.T, failures int, status goipp.Status) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+
+	var requests atomic.Int32
+	server := newTestIPPServer(t, func(r *http.Request, req *goipp.Message) *goipp.Message {
+		_, _ = io.Copy(io.Discard, r.Body)
+		if int(requests.Add(1)) <= failures {
+			return goipp.NewResponse(goipp.DefaultVersion, status, req.RequestID)
+		}
+		resp := mockPrinterResponse(req)
+		resp.Job.Add(goipp.MakeAttr("job-id", goipp.TagInteger, goipp.Integer(42)))
+		return resp
+	})
+	return server, &requests
+}
+
+// Test helper: an HTTP client whose first failures requests fail to connect,
+// optionally after reading read bytes of the request body
+func dialFailingClient(failures int, read int64) (*http.Client, *atomic.Int32) {
+	var attempts atomic.Int32
+	transport := roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		if int(attempts.Add(1)) <= failures {
+			if read > 0 {
+				_, _ = io.CopyN(io.Discard, r.Body, read)
+			}
+			return nil, &net.OpError{Op: "dial", Net: "tcp", Err: &net.AddrError{Err: "connection refused"}}
+		}
+		return http.DefaultTransport.RoundTrip(r)
+	})
+	return &http.Client{Transport: transport}, &attempts
+}
+
+func TestClient_Retry_Attributes(t *testing.T) {
+	tests := []struct {
+		name         string
+		failures     int
+		status       goipp.Status
+		retries      int
+		wantErr      bool
+		wantRequests int32
+	}{
+		{"busy then ok", 2, goipp.StatusErrorBusy, 3, false, 3},
+		{"temporary then ok", 1, goipp.StatusErrorTemporary, 3, false, 2},
+		{"retries exhausted", 5, goipp.StatusErrorBusy, 2, true, 3},
+		{"retrying disabled", 1, goipp.StatusErrorBusy, 0, true, 1},
+		{"not found is permanent", 1, goipp.StatusErrorNotFound, 3, true, 1},
+		{"not accepting jobs is permanent", 1, goipp.StatusErrorNotAcceptingJobs, 3, true, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, requests := newFlakyServer(t, tt.failures, tt.status)
+
+			var retries []int
+			c := NewClient(server.URL)
+			c.Retry = testRetryPolicy(tt.retries)
+			c.Retry.OnRetry = func(attempt int, _ time.Duration, _ error) {
+				retries = append(retries, attempt)
+			}
+
+			_, err := c.Attributes(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Attributes() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := requests.Load(); got != tt.wantRequests {
+				t.Errorf("requests = %d, want %d", got, tt.wantRequests)
+			}
+			if len(retries) != int(tt.wantRequests)-1 {
+				t.Errorf("OnRetry called %d times, want %d", len(retries), tt.wantRequests-1)
+			}
+		})
+	}
+}
+
+func TestClient_Retry_PrintJob(t *testing.T) {
+	t.Run("busy is not retried", func(t *testing.T) {
+		server, requests := newFlakyServer(t, 1, goipp.StatusErrorBusy)
+
+		c := NewClient(server.URL)
+		c.Retry = testRetryPolicy(3)
+		if _, err := c.Print(context.Background(), "doc.pdf", FormatPDF, strings.NewReader("%PDF"), DefaultPrintOptions()); err == nil {
+			t.Fatal("Print() expected error")
+		}
+		if got := requests.Load(); got != 1 {
+			t.Errorf("requests = %d, want 1 (Print-Job must not be resent)", got)
+		}
+	})
+
+	t.Run("connection refused before sending", func(t *testing.T) {
+		var received string
+		server := newTestPrintServer(t, func(_ *http.Request, _ *goipp.Message, doc io.Reader) {
+			data, _ := io.ReadAll(doc)
+			received = string(data)
+		})
+
+		httpClient, attempts := dialFailingClient(2, 0)
+		c := NewClient(server.URL)
+		c.HTTPClient = httpClient
+		c.Retry = testRetryPolicy(3)
+
+		result, err := c.Print(context.Background(), "doc.pdf", FormatPDF, strings.NewReader("%PDF-1.7"), DefaultPrintOptions())
+		if err != nil {
+			t.Fatalf("Print() error = %v", err)
+		}
+		if result.JobID != 42 {
+			t.Errorf("job ID = %d, want 42", result.JobID)
+		}
+		if got := attempts.Load(); got != 3 {
+			t.Errorf("attempts = %d, want 3", got)
+		}
+		if received != "%PDF-1.7" {
+			t.Errorf("document = %q, want %q", received, "%PDF-1.7")
+		}
+	})
+
+	t.Run("dump written once", func(t *testing.T) {
+		server := newTestPrintServer(t, func(_ *http.Request, _ *goipp.Message, doc io.Reader) {
+			_, _ = io.Copy(io.Discard, doc)
+		})
+
+		// The failed attempt reads part of the IPP header, but no document
+		httpClient, attempts := dialFailingClient(1, 16)
+		var dump bytes.Buffer
+		c := NewClient(server.URL)
+		c.HTTPClient = httpClient
+		c.Retry = testRetryPolicy(3)
+		c.DumpIPP = &dump
+
+		if _, err := c.Print(context.Background(), "doc.pdf", FormatPDF, strings.NewReader("%PDF-1.7"), DefaultPrintOptions()); err != nil {
+			t.Fatalf("Print() error = %v", err)
+		}
+		if got := attempts.Load(); got != 2 {
+			t.Errorf("attempts = %d, want 2", got)
+		}
+
+		// One IPP header followed by the document, nothing repeated
+		r := bytes.NewReader(dump.Bytes())
+		var msg goipp.Message
+		if err := msg.Decode(r); err != nil {
+			t.Fatalf("dump does not start with a valid IPP request: %v", err)
+		}
+		if goipp.Op(msg.Code) != goipp.OpPrintJob {
+			t.Errorf("dumped operation = %s, want Print-Job", goipp.Op(msg.Code))
+		}
+		if rest, _ := io.ReadAll(r); string(rest) != "%PDF-1.7" {
+			t.Errorf("dump continues with %q, want only the document", rest)
+		}
+	})
+
+	t.Run("document already read", func(t *testing.T) {
+		server := newTestPrintServer(t, func(_ *http.Request, _ *goipp.Message, doc io.Reader) {
+			_, _ = io.Copy(io.Discard, doc)
+		})
+
+		// Read past the IPP header into the document before failing
+		httpClient, attempts := dialFailingClient(1, 4096)
+		c := NewClient(server.URL)
+		c.HTTPClient = httpClient
+		c.Retry = testRetryPolicy(3)
+
+		if _, err := c.Print(context.Background(), "doc.pdf", FormatPDF, strings.NewReader("%PDF-1.7"), DefaultPrintOptions()); err == nil {
+			t.Fatal("Print() expected error")
+		}
+		if got := attempts.Load(); got != 1 {
+			t.Errorf("attempts = %d, want 1", got)
+		}
+	})
+}
+
+func TestClient_Retry_Canceled(t *testing.T) {
+	server, requests := newFlakyServer(t, 10, goipp.StatusErrorBusy)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c := NewClient(server.URL)
+	c.Retry = RetryPolicy{MaxRetries: 5, InitialDelay: time.Hour}
+	c.Retry.OnRetry = func(int, time.Duration, error) { cancel() }
+
+	if _, err := c.Attributes(ctx); !IsPrinterBusy(err) {
+		t.Errorf("Attributes() error = %v, want the last printer error", err)
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("requests = %d, want 1", got)
+	}
+}
+
+func TestRetryPolicy_Delay(t *testing.T) {
+	p := RetryPolicy{InitialDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+
+	tests := []struct {
+		retry int
+		max   time.Duration
+	}{
+		{1, 100 * time.Millisecond},
+		{2, 200 * time.Millisecond},
+		{3, 400 * time.Millisecond},
+		{5, time.Second},
+		{50, time.Second},
+	}
+
+	for _, tt := range tests {
+		for range 20 {
+			d := p.delay(tt.retry)
+			if d < tt.max/2 || d > tt.max {
+				t.Errorf("delay(%d) = %v, want between %v and %v", tt.retry, d, tt.max/2, tt.max)
+			}
+		}
+	}
+}
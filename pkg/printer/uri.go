@@ -0,0 +1,101 @@
+package printer
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// DefaultIPPPort is the port used for ipp:// and ipps:// URIs without an explicit port
+const DefaultIPPPort = "631"
+
+// TLSOptions configures TLS for https:// and ipps:// printers
+type TLSOptions struct {
+	CAFile             string `json:"ca_file,omitempty"`     // PEM file with additional CA certificates to trust
+	InsecureSkipVerify bool   `json:"insecure,omitempty"`    // Accept any certificate, e.g. a printer's self-signed one
+	CertFile           string `json:"client_cert,omitempty"` // PEM client certificate for mutual TLS
+	KeyFile            string `json:"client_key,omitempty"`  // PEM private key for CertFile
+}
+
+// transportURL returns the HTTP URL used to reach a printer URI.
+// ipp:// becomes http:// and ipps:// becomes https://, with port 631 when
+// none is given. http:// and https:// URIs are returned unchanged.
+func transportURL(printerURI string) (string, error) {
+	u, err := url.Parse(printerURI)
+	if err != nil {
+		return "", fmt.Errorf("parsing printer URI: %w", err)
+	}
+
+	switch strings.ToLower(u.Scheme) {
+	case "http", "https":
+		return printerURI, nil
+	case "ipp":
+		u.Scheme = "http"
+	case "ipps":
+		u.Scheme = "https"
+	default:
+		return "", fmt.Errorf("unsupported printer URI scheme %q (use ipp, ipps, http or https)", u.Scheme)
+	}
+
+	if u.Port() == "" {
+		u.Host = net.JoinHostPort(u.Hostname(), DefaultIPPPort)
+	}
+
+	return u.String(), nil
+}
+
+// NewHTTPClient returns an HTTP client with the given TLS settings, for use
+// as Client.HTTPClient with https:// and ipps:// printers
+func NewHTTPClient(opts TLSOptions) (*http.Client, error) {
+	tlsConfig, err := opts.Config()
+	if err != nil {
+		return nil, err
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+
+	return &http.Client{Transport: transport}, nil
+}
+
+// Config builds a tls.Config from the options
+func (o TLSOptions) Config() (*tls.Config, error) {
+	config := &tls.Config{
+		InsecureSkipVerify: o.InsecureSkipVerify,
+	}
+
+	if o.CAFile != "" {
+		pem, err := os.ReadFile(o.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading CA file: %w", err)
+		}
+
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in CA file %s", o.CAFile)
+		}
+		config.RootCAs = pool
+	}
+
+	if o.CertFile != "" || o.KeyFile != "" {
+		if o.CertFile == "" || o.KeyFile == "" {
+			return nil, fmt.Errorf("client certificate requires both a certificate and a key file")
+		}
+
+		cert, err := tls.LoadX509KeyPair(o.CertFile, o.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("loading client certificate: %w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	return config, nil
+}
@@ -0,0 +1,230 @@
+package printer
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/OpenPrinting/goipp"
+)
+
+// Test helper: start a TLS IPP server and return it with its ipps:// URI.
+// configure may adjust the server's TLS settings before it starts.
+func newTestIPPSServer(t *testing.T, configure func(*tls.Config)) (*httptest.Server, string, *goipp.Message) {
+	t.Helper()
+
+	received := &goipp.Message{}
+	server := httptest.NewUnstartedServer(ippHandler(func(_ *http.Request, req *goipp.Message) *goipp.Message {
+		*received = *req
+		return mockPrinterResponse(req)
+	}))
+	server.TLS = &tls.Config{}
+	if configure != nil {
+		configure(server.TLS)
+	}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+
+	uri := "ipps://" + strings.TrimPrefix(server.URL, "https://") + "/ipp/print"
+	return server, uri, received
+}
+
+// Test helper: write PEM blocks to a temporary file and return its path
+func writePEM(t *testing.T, name, blockType string, der []byte) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	data := pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("writing %s: %v", name, err)
+	}
+	return path
+}
+
+// Test helper: create a self-signed client certificate and key
+func createClientCert(t *testing.T) (certFile, keyFile string, cert *x509.Certificate) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "epson-printing test client"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("creating certificate: %v", err)
+	}
+	cert, err = x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("parsing certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("marshaling key: %v", err)
+	}
+
+	certFile = writePEM(t, "client.pem", "CERTIFICATE", der)
+	keyFile = writePEM(t, "client-key.pem", "EC PRIVATE KEY", keyDER)
+	return certFile, keyFile, cert
+}
+
+func TestTransportURL(t *testing.T) {
+	tests := []struct {
+		name      string
+		uri       string
+		expected  string
+		expectErr bool
+	}{
+		{"ipp default port", "ipp://printer.local/ipp/print", "http://printer.local:631/ipp/print", false},
+		{"ipp explicit port", "ipp://printer.local:8631/ipp/print", "http://printer.local:8631/ipp/print", false},
+		{"ipps default port", "ipps://printer.local/ipp/print", "https://printer.local:631/ipp/print", false},
+		{"ipp uppercase scheme", "IPP://printer.local/ipp/print", "http://printer.local:631/ipp/print", false},
+		{"ipp IPv6 host", "ipp://[fe80::1]/ipp/print", "http://[fe80::1]:631/ipp/print", false},
+		{"http unchanged", "http://localhost:631/printers/EPSON", "http://localhost:631/printers/EPSON", false},
+		{"https unchanged", "https://printer.local/ipp/print", "https://printer.local/ipp/print", false},
+		{"unsupported scheme", "lpd://printer.local/queue", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := transportURL(tt.uri)
+			if tt.expectErr {
+				if err == nil {
+					t.Errorf("transportURL(%q) expected error, got %q", tt.uri, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("transportURL(%q) error = %v", tt.uri, err)
+			}
+			if got != tt.expected {
+				t.Errorf("transportURL(%q) = %q, expected %q", tt.uri, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestClient_IPPS(t *testing.T) {
+	server, uri, received := newTestIPPSServer(t, nil)
+	caFile := writePEM(t, "ca.pem", "CERTIFICATE", server.Certificate().Raw)
+
+	tests := []struct {
+		name      string
+		opts      TLSOptions
+		expectErr bool
+	}{
+		{"untrusted certificate", TLSOptions{}, true},
+		{"custom CA", TLSOptions{CAFile: caFile}, false},
+		{"skip verify", TLSOptions{InsecureSkipVerify: true}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpClient, err := NewHTTPClient(tt.opts)
+			if err != nil {
+				t.Fatalf("NewHTTPClient() error = %v", err)
+			}
+
+			c := NewClient(uri)
+			c.HTTPClient = httpClient
+			_, err = c.Info(context.Background())
+
+			if tt.expectErr {
+				if err == nil {
+					t.Error("expected TLS error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Info() error = %v", err)
+			}
+
+			// The original ipps:// URI must be sent as printer-uri
+			attr := findAttribute(received.Operation, "printer-uri")
+			if attr == nil || attr.Values[0].V.String() != uri {
+				t.Errorf("expected printer-uri %q, got %v", uri, attr)
+			}
+		})
+	}
+}
+
+func TestClient_IPPSClientCertificate(t *testing.T) {
+	certFile, keyFile, cert := createClientCert(t)
+
+	_, uri, _ := newTestIPPSServer(t, func(config *tls.Config) {
+		pool := x509.NewCertPool()
+		pool.AddCert(cert)
+		config.ClientCAs = pool
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	})
+
+	tests := []struct {
+		name      string
+		opts      TLSOptions
+		expectErr bool
+	}{
+		{"without client certificate", TLSOptions{InsecureSkipVerify: true}, true},
+		{"with client certificate", TLSOptions{InsecureSkipVerify: true, CertFile: certFile, KeyFile: keyFile}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpClient, err := NewHTTPClient(tt.opts)
+			if err != nil {
+				t.Fatalf("NewHTTPClient() error = %v", err)
+			}
+
+			c := NewClient(uri)
+			c.HTTPClient = httpClient
+			_, err = c.Info(context.Background())
+			if (err != nil) != tt.expectErr {
+				t.Errorf("Info() error = %v, expectErr %v", err, tt.expectErr)
+			}
+		})
+	}
+}
+
+func TestTLSOptions_Config_Errors(t *testing.T) {
+	invalidCA := filepath.Join(t.TempDir(), "invalid.pem")
+	if err := os.WriteFile(invalidCA, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		opts TLSOptions
+	}{
+		{"missing CA file", TLSOptions{CAFile: "/nonexistent/ca.pem"}},
+		{"CA file without certificates", TLSOptions{CAFile: invalidCA}},
+		{"certificate without key", TLSOptions{CertFile: "client.pem"}},
+		{"missing certificate files", TLSOptions{CertFile: "/nonexistent/c.pem", KeyFile: "/nonexistent/k.pem"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.opts.Config(); err == nil {
+				t.Error("expected error, got nil")
+			}
+		})
+	}
+}
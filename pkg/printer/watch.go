@@ -0,0 +1,483 @@
+package printer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RuleKind is the condition checked by a watch Rule
+type RuleKind int
+
+const (
+	RuleInkBelow    RuleKind = iota // An ink tank is below Level percent
+	RuleState                       // The printer is in State
+	RuleReason                      // printer-state-reasons contains Reason
+	RuleUnreachable                 // The printer status cannot be read
+)
+
+// InkHysteresis is how far, in percent, an ink level must rise above the
+// threshold of an ink rule before its alert is resolved, so a level
+// hovering around the threshold does not alert on every poll
+const InkHysteresis = 5
+
+// DefaultWatchRules are the rules used by 'print watch' when none are given
+var DefaultWatchRules = []string{"ink<15", "state:stopped>5m", "reason:media-empty", "unreachable>10m"}
+
+// Rule is a condition on the printer status that raises an alert.
+// Rules are written as:
+//
+//	ink<15               any ink tank below 15%
+//	ink:Cyan<10          the Cyan tank below 10%
+//	state:stopped>5m     printer stopped for more than 5 minutes
+//	reason:media-empty   printer-state-reasons contains media-empty(-error, -warning, ...)
+//	unreachable>10m      printer not answering for more than 10 minutes
+//
+// State, reason and unreachable rules alert immediately without a duration.
+type Rule struct {
+	Kind   RuleKind
+	Ink    string        // RuleInkBelow: tank name; empty for any tank
+	Level  int           // RuleInkBelow: threshold in percent
+	State  string        // RuleState: Idle, Processing or Stopped
+	Reason string        // RuleReason: state reason keyword, e.g. media-empty
+	For    time.Duration // How long the condition must hold before alerting
+}
+
+// ParseRule parses a rule such as "ink<15" or "state:stopped>5m"
+func ParseRule(s string) (Rule, error) {
+	s = strings.TrimSpace(s)
+
+	// Only ink rules compare with <
+	if condition, level, ok := strings.Cut(s, "<"); ok {
+		kind, name, _ := strings.Cut(condition, ":")
+		if !strings.EqualFold(strings.TrimSpace(kind), "ink") {
+			return Rule{}, fmt.Errorf("invalid rule %q: only ink rules compare with <", s)
+		}
+		percent, err := strconv.Atoi(strings.TrimSuffix(strings.TrimSpace(level), "%"))
+		if err != nil || percent <= 0 || percent > 100 {
+			return Rule{}, fmt.Errorf("invalid rule %q: ink threshold must be a percentage (1-100)", s)
+		}
+		return Rule{Kind: RuleInkBelow, Ink: strings.TrimSpace(name), Level: percent}, nil
+	}
+
+	// state:, reason: and unreachable take an optional >duration
+	condition, duration, timed := strings.Cut(s, ">")
+	var d time.Duration
+	if timed {
+		var err error
+		if d, err = time.ParseDuration(strings.TrimSpace(duration)); err != nil || d < 0 {
+			return Rule{}, fmt.Errorf("invalid rule %q: bad duration %q", s, duration)
+		}
+	}
+	kind, arg, _ := strings.Cut(condition, ":")
+	arg = strings.TrimSpace(arg)
+
+	switch strings.ToLower(strings.TrimSpace(kind)) {
+	case "state":
+		for _, state := range []string{"Idle", "Processing", "Stopped"} {
+			if strings.EqualFold(arg, state) {
+				return Rule{Kind: RuleState, State: state, For: d}, nil
+			}
+		}
+		return Rule{}, fmt.Errorf("invalid rule %q: state must be idle, processing or stopped", s)
+	case "reason":
+		if arg == "" {
+			return Rule{}, fmt.Errorf("invalid rule %q: missing state reason", s)
+		}
+		return Rule{Kind: RuleReason, Reason: strings.ToLower(arg), For: d}, nil
+	case "unreachable":
+		if arg != "" {
+			return Rule{}, fmt.Errorf("invalid rule %q: unreachable takes no argument", s)
+		}
+		return Rule{Kind: RuleUnreachable, For: d}, nil
+	}
+	return Rule{}, fmt.Errorf("invalid rule %q (use e.g. ink<15, state:stopped>5m, reason:media-empty or unreachable>10m)", s)
+}
+
+// ParseRules parses a list of rules
+func ParseRules(rules []string) ([]Rule, error) {
+	parsed := make([]Rule, 0, len(rules))
+	for _, s := range rules {
+		rule, err := ParseRule(s)
+		if err != nil {
+			return nil, err
+		}
+		parsed = append(parsed, rule)
+	}
+	return parsed, nil
+}
+
+// String returns the rule in the syntax accepted by ParseRule
+func (r Rule) String() string {
+	var s string
+	switch r.Kind {
+	case RuleInkBelow:
+		if r.Ink != "" {
+			return fmt.Sprintf("ink:%s<%d", r.Ink, r.Level)
+		}
+		return fmt.Sprintf("ink<%d", r.Level)
+	case RuleState:
+		s = "state:" + strings.ToLower(r.State)
+	case RuleReason:
+		s = "reason:" + r.Reason
+	case RuleUnreachable:
+		s = "unreachable"
+	default:
+		return fmt.Sprintf("Rule(%d)", r.Kind)
+	}
+	if r.For > 0 {
+		s += ">" + shortDuration(r.For)
+	}
+	return s
+}
+
+// match is a condition of a rule that currently holds
+type match struct {
+	rule    Rule
+	detail  string // Tank name or state reason the condition is about
+	message string
+}
+
+// key identifies the condition across polls
+func (m match) key() string {
+	return m.rule.String() + "/" + m.detail
+}
+
+// matches returns the conditions of the rule that hold for a status.
+// firing reports whether the alert of a condition was sent, for hysteresis.
+func (r Rule) matches(info *Info, infoErr error, firing func(m match) bool) []match {
+	if r.Kind == RuleUnreachable {
+		if infoErr == nil {
+			return nil
+		}
+		return []match{{rule: r, message: fmt.Sprintf("Printer unreachable: %v", infoErr)}}
+	}
+
+	var matches []match
+	switch r.Kind {
+	case RuleInkBelow:
+		for _, ink := range info.InkLevels {
+			if r.Ink != "" && !strings.EqualFold(ink.Name, r.Ink) {
+				continue
+			}
+			m := match{rule: r, detail: ink.Name,
+				message: fmt.Sprintf("%s ink low: %d%% (below %d%%)", ink.Name, ink.Level, r.Level)}
+			switch {
+			case ink.Level < 0:
+				// Unknown level (-1, -2 or -3): not an empty tank, and not
+				// a refill of one that was low either
+				if firing(m) {
+					m.message = fmt.Sprintf("%s ink level unknown (was below %d%%)", ink.Name, r.Level)
+					matches = append(matches, m)
+				}
+			case ink.Level < r.Level || firing(m) && ink.Level < r.Level+InkHysteresis:
+				matches = append(matches, m)
+			}
+		}
+	case RuleState:
+		if strings.EqualFold(info.State, r.State) {
+			matches = append(matches, match{rule: r, message: withStateMessage("Printer "+strings.ToLower(r.State), info)})
+		}
+	case RuleReason:
+		for _, reason := range splitReasons(info.StateReasons) {
+			if reasonMatches(reason, r.Reason) {
+				matches = append(matches, match{rule: r, detail: reason, message: withStateMessage("Printer reports "+reason, info)})
+			}
+		}
+	}
+	return matches
+}
+
+// resolvedMessage describes a condition that no longer holds
+func (m match) resolvedMessage(info *Info) string {
+	switch m.rule.Kind {
+	case RuleInkBelow:
+		for _, ink := range info.InkLevels {
+			if ink.Name == m.detail {
+				return fmt.Sprintf("%s ink back at %d%%", ink.Name, ink.Level)
+			}
+		}
+		return fmt.Sprintf("%s ink no longer reported", m.detail)
+	case RuleState:
+		return fmt.Sprintf("Printer no longer %s (now %s)", strings.ToLower(m.rule.State), info.State)
+	case RuleReason:
+		return fmt.Sprintf("Printer no longer reports %s", m.detail)
+	default:
+		return "Printer reachable again"
+	}
+}
+
+// withStateMessage appends the printer-state-message, if any
+func withStateMessage(message string, info *Info) string {
+	if info.StateMessage != "" {
+		return message + ": " + info.StateMessage
+	}
+	return message
+}
+
+// splitReasons returns the keywords in Info.StateReasons
+func splitReasons(reasons string) []string {
+	var keywords []string
+	for reason := range strings.SplitSeq(reasons, ",") {
+		if reason = strings.TrimSpace(reason); reason != "" && reason != "none" {
+			keywords = append(keywords, reason)
+		}
+	}
+	return keywords
+}
+
+// reasonMatches reports whether a printer-state-reasons keyword matches a
+// rule keyword, ignoring the -error, -warning and -report severity suffixes
+func reasonMatches(reason, keyword string) bool {
+	return strings.EqualFold(trimSeverity(reason), keyword)
+}
+
+// shortDuration formats a duration without trailing zero units: 5m, not 5m0s
+func shortDuration(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
+
+// Alert is a notification that a rule started or stopped matching
+type Alert struct {
+	Time     time.Time `json:"time"`
+	Printer  string    `json:"printer"`
+	Rule     string    `json:"rule"` // The rule in ParseRule syntax, e.g. "ink<15"
+	Message  string    `json:"message"`
+	Resolved bool      `json:"resolved"`       // The condition no longer holds
+	Repeat   bool      `json:"repeat"`         // The condition still holds after Watcher.Repeat
+	Info     *Info     `json:"info,omitempty"` // Status when the alert was raised; nil when unreachable
+}
+
+// Status returns "ALERT", "REPEAT" or "RESOLVED"
+func (a Alert) Status() string {
+	switch {
+	case a.Resolved:
+		return "RESOLVED"
+	case a.Repeat:
+		return "REPEAT"
+	default:
+		return "ALERT"
+	}
+}
+
+// String returns the alert as a single line of text
+func (a Alert) String() string {
+	return fmt.Sprintf("%s [%s] %s %s: %s", a.Time.Local().Format("2006-01-02 15:04:05"),
+		a.Printer, a.Status(), a.Rule, a.Message)
+}
+
+// Watcher checks the printer status against rules and sends alerts to its
+// notifiers. An alert is sent once when its condition has held for the
+// rule's duration and once more when the condition is resolved; ink alerts
+// resolve only after the level rises InkHysteresis above the threshold.
+// Delivery is tracked per notifier, so a notifier that failed gets the
+// alert again on the next check without repeating it on the others. While
+// the printer is unreachable, other alerts keep their state.
+type Watcher struct {
+	Printer   string // Printer name used in alerts
+	Rules     []Rule
+	Notifiers []Notifier
+
+	// Repeat re-sends alerts that are still active after this long;
+	// 0 sends each alert once
+	Repeat time.Duration
+
+	// OnError is called with errors of notifiers and status requests
+	// in Run, e.g. for logging
+	OnError func(err error)
+
+	conditions map[string]*condition
+}
+
+// condition is the tracked state of a match across polls
+type condition struct {
+	match
+	since    time.Time         // When the condition started to hold
+	resolved bool              // The condition no longer holds
+	notified map[int]time.Time // Notifier index to when it was last sent the alert
+}
+
+// NewWatcher returns a Watcher for the named printer
+func NewWatcher(printer string, rules []Rule, notifiers ...Notifier) *Watcher {
+	return &Watcher{Printer: printer, Rules: rules, Notifiers: notifiers}
+}
+
+// Check evaluates the rules against a printer status, or the error reading
+// it, and sends the alerts that are due. It returns the alerts delivered by
+// at least one notifier. A notifier that failed is sent the alert again on
+// the next Check, and a resolved condition is only forgotten once every
+// notifier that was alerted has been sent its resolution.
+func (w *Watcher) Check(ctx context.Context, info *Info, infoErr error, now time.Time) ([]Alert, error) {
+	if w.conditions == nil {
+		w.conditions = make(map[string]*condition)
+	}
+	firing := func(m match) bool {
+		c, ok := w.conditions[m.key()]
+		return ok && len(c.notified) > 0
+	}
+
+	holding := make(map[string]match)
+	for _, rule := range w.Rules {
+		if infoErr != nil && rule.Kind != RuleUnreachable {
+			continue
+		}
+		for _, m := range rule.matches(info, infoErr, firing) {
+			holding[m.key()] = m
+		}
+	}
+
+	var sent []Alert
+	var errs []error
+	for _, key := range sortedKeys(w.conditions, holding) {
+		c, tracked := w.conditions[key]
+		if infoErr != nil && tracked && c.rule.Kind != RuleUnreachable {
+			continue // The status is unknown: keep the condition as it was
+		}
+
+		m, holds := holding[key]
+		switch {
+		case !holds:
+			c.resolved = true
+			alert := w.alert(c.match, c.resolvedMessage(info), info, now)
+			alert.Resolved = true
+			delivered := false
+			for _, i := range slices.Sorted(maps.Keys(c.notified)) {
+				if err := w.notify(ctx, i, alert); err != nil {
+					errs = append(errs, err)
+					continue
+				}
+				delete(c.notified, i)
+				delivered = true
+			}
+			if delivered {
+				sent = append(sent, alert)
+			}
+			if len(c.notified) == 0 {
+				delete(w.conditions, key)
+			}
+			continue
+		case !tracked:
+			c = &condition{since: now, notified: make(map[int]time.Time)}
+			w.conditions[key] = c
+		case c.resolved:
+			// Holds again before every resolution was delivered: notifiers
+			// that were not told it resolved stay alerted
+			c.resolved, c.since = false, now
+		}
+		c.match = m
+
+		alert := w.alert(m, m.message, info, now)
+		repeat := alert
+		repeat.Repeat = true
+		var delivered, repeated bool
+		for i := range max(len(w.Notifiers), 1) {
+			last, notified := c.notified[i]
+			switch {
+			case !notified && now.Sub(c.since) >= m.rule.For:
+				if err := w.notify(ctx, i, alert); err != nil {
+					errs = append(errs, err)
+					continue
+				}
+				delivered = true
+			case notified && w.Repeat > 0 && now.Sub(last) >= w.Repeat:
+				if err := w.notify(ctx, i, repeat); err != nil {
+					errs = append(errs, err)
+					continue
+				}
+				repeated = true
+			default:
+				continue
+			}
+			c.notified[i] = now
+		}
+		if delivered {
+			sent = append(sent, alert)
+		}
+		if repeated {
+			sent = append(sent, repeat)
+		}
+	}
+	return sent, errors.Join(errs...)
+}
+
+// Run polls the printer status every interval and checks the rules until
+// ctx is canceled
+func (w *Watcher) Run(ctx context.Context, client *Client, interval time.Duration) error {
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		info, err := client.Info(ctx)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			w.report(fmt.Errorf("reading printer status: %w", err))
+		}
+		if _, err := w.Check(ctx, info, err, time.Now()); err != nil {
+			w.report(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// alert builds an alert for a match
+func (w *Watcher) alert(m match, message string, info *Info, now time.Time) Alert {
+	return Alert{Time: now, Printer: w.Printer, Rule: m.rule.String(), Message: message, Info: info}
+}
+
+// notify sends an alert to the notifier with the given index. Without
+// notifiers, alerts are only returned by Check.
+func (w *Watcher) notify(ctx context.Context, i int, alert Alert) error {
+	if i >= len(w.Notifiers) {
+		return nil
+	}
+	if err := w.Notifiers[i].Notify(ctx, alert); err != nil {
+		return fmt.Errorf("sending %s %q: %w", alert.Status(), alert.Message, err)
+	}
+	return nil
+}
+
+// report passes an error to OnError
+func (w *Watcher) report(err error) {
+	if w.OnError != nil {
+		w.OnError(err)
+	}
+}
+
+// sortedKeys returns the keys of tracked and holding conditions in order,
+// so alerts are sent in a stable order
+func sortedKeys(conditions map[string]*condition, holding map[string]match) []string {
+	keys := make([]string, 0, len(conditions)+len(holding))
+	for key := range conditions {
+		keys = append(keys, key)
+	}
+	for key := range holding {
+		if _, ok := conditions[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+	return keys
+}
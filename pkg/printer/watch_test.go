@@ -0,0 +1,346 @@
+package printer
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Eric-Eklund/epson-printing/pkg/printer/ipptest"
+)
+
+// Test helper: a notifier that records alerts and can be made to fail
+type recordingNotifier struct {
+	mu     sync.Mutex
+	alerts []Alert
+	err    error
+}
+
+func (n *recordingNotifier) Notify(_ context.Context, alert Alert) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.err != nil {
+		return n.err
+	}
+	n.alerts = append(n.alerts, alert)
+	return nil
+}
+
+// messages returns "STATUS message" of the recorded alerts and clears them
+func (n *recordingNotifier) messages() []string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	var messages []string
+	for _, a := range n.alerts {
+		messages = append(messages, a.Status()+" "+a.Message)
+	}
+	n.alerts = nil
+	return messages
+}
+
+// Test helper: a printer status with Black and Cyan levels
+func watchInfo(state string, black, cyan int) *Info {
+	return &Info{
+		State:        state,
+		StateReasons: "none",
+		InkLevels: []InkLevel{
+			{Name: "Black", Level: black},
+			{Name: "Cyan", Level: cyan},
+		},
+	}
+}
+
+func TestParseRule(t *testing.T) {
+	tests := []struct {
+		rule    string
+		want    Rule
+		wantErr bool
+	}{
+		{rule: "ink<15", want: Rule{Kind: RuleInkBelow, Level: 15}},
+		{rule: " ink < 15% ", want: Rule{Kind: RuleInkBelow, Level: 15}},
+		{rule: "ink:Photo Black<10", want: Rule{Kind: RuleInkBelow, Ink: "Photo Black", Level: 10}},
+		{rule: "state:stopped>5m", want: Rule{Kind: RuleState, State: "Stopped", For: 5 * time.Minute}},
+		{rule: "STATE:Processing", want: Rule{Kind: RuleState, State: "Processing"}},
+		{rule: "reason:media-empty", want: Rule{Kind: RuleReason, Reason: "media-empty"}},
+		{rule: "reason:Media-Jam>30s", want: Rule{Kind: RuleReason, Reason: "media-jam", For: 30 * time.Second}},
+		{rule: "unreachable>10m", want: Rule{Kind: RuleUnreachable, For: 10 * time.Minute}},
+		{rule: "ink<0", wantErr: true},
+		{rule: "ink<lots", wantErr: true},
+		{rule: "state:stopped<5m", wantErr: true},
+		{rule: "state:asleep", wantErr: true},
+		{rule: "state:stopped>soon", wantErr: true},
+		{rule: "reason:", wantErr: true},
+		{rule: "unreachable:now", wantErr: true},
+		{rule: "toner<10", wantErr: true},
+		{rule: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rule, func(t *testing.T) {
+			got, err := ParseRule(tt.rule)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseRule(%q) = %+v, want error", tt.rule, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseRule(%q) error = %v", tt.rule, err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseRule(%q) = %+v, want %+v", tt.rule, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRule_String(t *testing.T) {
+	for _, s := range []string{"ink<15", "ink:Photo Black<10", "state:stopped>5m", "state:idle",
+		"reason:media-empty>1h30m", "unreachable>2h", "unreachable>1m30s"} {
+		rule, err := ParseRule(s)
+		if err != nil {
+			t.Fatalf("ParseRule(%q) error = %v", s, err)
+		}
+		if got := rule.String(); got != s {
+			t.Errorf("ParseRule(%q).String() = %s", s, got)
+		}
+	}
+
+	if _, err := ParseRules(DefaultWatchRules); err != nil {
+		t.Errorf("DefaultWatchRules: %v", err)
+	}
+}
+
+func TestWatcher_Check(t *testing.T) {
+	rules, err := ParseRules([]string{"ink<15", "state:stopped>5m", "reason:media-empty", "unreachable>10m"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	notifier := &recordingNotifier{}
+	w := NewWatcher("photo", rules, notifier)
+
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	mediaEmpty := watchInfo("Stopped", 16, 50)
+	mediaEmpty.StateReasons = "media-empty-error, marker-supply-low-warning"
+	mediaEmpty.StateMessage = "Paper out"
+
+	steps := []struct {
+		name    string
+		minutes int
+		info    *Info
+		err     error
+		want    []string
+	}{
+		{name: "all fine", minutes: 0, info: watchInfo("Idle", 50, 50)},
+		{name: "ink low", minutes: 1, info: watchInfo("Idle", 14, 50), want: []string{"ALERT Black ink low: 14% (below 15%)"}},
+		{name: "deduplicated", minutes: 2, info: watchInfo("Idle", 13, 50)},
+		{name: "hysteresis holds", minutes: 3, info: watchInfo("Idle", 16, 50)},
+		{
+			name: "paper out", minutes: 4, info: mediaEmpty,
+			want: []string{"ALERT Printer reports media-empty-error: Paper out"},
+		},
+		{name: "stopped not long enough", minutes: 8, info: mediaEmpty},
+		{name: "unreachable not long enough", minutes: 9, err: errors.New("connection refused")},
+		{name: "stopped long enough", minutes: 10, info: mediaEmpty, want: []string{"ALERT Printer stopped: Paper out"}},
+		{
+			name: "resolved", minutes: 11, info: watchInfo("Idle", 20, 50),
+			want: []string{
+				"RESOLVED Black ink back at 20%",
+				"RESOLVED Printer no longer reports media-empty-error",
+				"RESOLVED Printer no longer stopped (now Idle)",
+			},
+		},
+		{name: "unreachable", minutes: 12, err: errors.New("connection refused")},
+		{
+			name: "unreachable long enough", minutes: 22, err: errors.New("connection refused"),
+			want: []string{"ALERT Printer unreachable: connection refused"},
+		},
+		{name: "back", minutes: 23, info: watchInfo("Idle", 20, 50), want: []string{"RESOLVED Printer reachable again"}},
+	}
+
+	for _, step := range steps {
+		now := start.Add(time.Duration(step.minutes) * time.Minute)
+		sent, err := w.Check(context.Background(), step.info, step.err, now)
+		if err != nil {
+			t.Fatalf("%s: Check() error = %v", step.name, err)
+		}
+		if got := notifier.messages(); !slices.Equal(got, step.want) {
+			t.Errorf("%s: alerts = %q, want %q", step.name, got, step.want)
+		}
+		if len(sent) != len(step.want) {
+			t.Errorf("%s: Check() returned %d alerts, want %d", step.name, len(sent), len(step.want))
+		}
+	}
+}
+
+func TestWatcher_Check_UnknownInkLevel(t *testing.T) {
+	rules, _ := ParseRules([]string{"ink<15", "ink:Cyan<10"})
+	notifier := &recordingNotifier{}
+	w := NewWatcher("photo", rules, notifier)
+
+	// RFC 8011 uses -1, -2 and -3 for levels the printer cannot tell
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	for i, level := range []int{-1, -2, -3} {
+		if _, err := w.Check(context.Background(), watchInfo("Idle", level, level), nil, now.Add(time.Duration(i)*time.Minute)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := notifier.messages(); len(got) != 0 {
+		t.Errorf("alerts for unknown levels: %q", got)
+	}
+
+	// A low tank whose level becomes unknown is not resolved
+	steps := []struct {
+		black int
+		want  []string
+	}{
+		{black: 10, want: []string{"ALERT Black ink low: 10% (below 15%)"}},
+		{black: -2},
+		{black: 60, want: []string{"RESOLVED Black ink back at 60%"}},
+	}
+	for i, step := range steps {
+		if _, err := w.Check(context.Background(), watchInfo("Idle", step.black, 50), nil, now.Add(time.Duration(10+i)*time.Minute)); err != nil {
+			t.Fatal(err)
+		}
+		if got := notifier.messages(); !slices.Equal(got, step.want) {
+			t.Errorf("Black at %d%%: alerts = %q, want %q", step.black, got, step.want)
+		}
+	}
+}
+
+func TestWatcher_Check_Repeat(t *testing.T) {
+	rules, _ := ParseRules([]string{"ink:Cyan<10"})
+	notifier := &recordingNotifier{}
+	w := NewWatcher("photo", rules, notifier)
+	w.Repeat = time.Hour
+
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	for _, minutes := range []int{0, 30, 60, 90, 120} {
+		if _, err := w.Check(context.Background(), watchInfo("Idle", 50, 5), nil, start.Add(time.Duration(minutes)*time.Minute)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := []string{"ALERT Cyan ink low: 5% (below 10%)", "REPEAT Cyan ink low: 5% (below 10%)", "REPEAT Cyan ink low: 5% (below 10%)"}
+	if got := notifier.messages(); !slices.Equal(got, want) {
+		t.Errorf("alerts = %q, want %q", got, want)
+	}
+}
+
+func TestWatcher_Check_NotifierFails(t *testing.T) {
+	rules, _ := ParseRules([]string{"ink<15"})
+	failing := &recordingNotifier{err: errors.New("mail server down")}
+	w := NewWatcher("photo", rules, failing)
+
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	sent, err := w.Check(context.Background(), watchInfo("Idle", 10, 50), nil, now)
+	if err == nil || len(sent) != 0 {
+		t.Fatalf("Check() = %v, %v; want an error and no alerts sent", sent, err)
+	}
+
+	// The undelivered alert is retried on the next check
+	failing.err = nil
+	sent, err = w.Check(context.Background(), watchInfo("Idle", 10, 50), nil, now.Add(time.Minute))
+	if err != nil || len(sent) != 1 {
+		t.Errorf("Check() = %v, %v; want the alert sent", sent, err)
+	}
+}
+
+func TestWatcher_Check_OneNotifierFails(t *testing.T) {
+	rules, _ := ParseRules([]string{"ink<15"})
+	working := &recordingNotifier{}
+	failing := &recordingNotifier{err: errors.New("mail server down")}
+	w := NewWatcher("photo", rules, working, failing)
+
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	steps := []struct {
+		name        string
+		black       int
+		failing     bool
+		wantErr     bool
+		wantWorking []string
+		wantFailing []string
+	}{
+		{
+			name: "alert reaches one notifier", black: 10, failing: true, wantErr: true,
+			wantWorking: []string{"ALERT Black ink low: 10% (below 15%)"},
+		},
+		{
+			name: "retried on the failed notifier only", black: 10,
+			wantFailing: []string{"ALERT Black ink low: 10% (below 15%)"},
+		},
+		{
+			name: "resolve reaches one notifier", black: 50, failing: true, wantErr: true,
+			wantWorking: []string{"RESOLVED Black ink back at 50%"},
+		},
+		{
+			name: "resolve retried on the failed notifier only", black: 50,
+			wantFailing: []string{"RESOLVED Black ink back at 50%"},
+		},
+		{name: "condition forgotten", black: 50},
+	}
+
+	for i, step := range steps {
+		if step.failing {
+			failing.err = errors.New("mail server down")
+		} else {
+			failing.err = nil
+		}
+
+		_, err := w.Check(context.Background(), watchInfo("Idle", step.black, 50), nil, start.Add(time.Duration(i)*time.Minute))
+		if (err != nil) != step.wantErr {
+			t.Errorf("%s: Check() error = %v, wantErr %v", step.name, err, step.wantErr)
+		}
+		if got := working.messages(); !slices.Equal(got, step.wantWorking) {
+			t.Errorf("%s: working notifier got %q, want %q", step.name, got, step.wantWorking)
+		}
+		if got := failing.messages(); !slices.Equal(got, step.wantFailing) {
+			t.Errorf("%s: failing notifier got %q, want %q", step.name, got, step.wantFailing)
+		}
+	}
+	if len(w.conditions) != 0 {
+		t.Errorf("conditions not forgotten after every resolution was delivered: %v", w.conditions)
+	}
+}
+
+func TestWatcher_Run(t *testing.T) {
+	p := ipptest.NewPrinter()
+	p.Markers = []ipptest.Marker{{Name: "Black", Color: "#000000", Level: 8}}
+	server := ipptest.NewServer(p)
+	defer server.Close()
+
+	rules, _ := ParseRules([]string{"ink<15"})
+	notifier := &recordingNotifier{}
+	w := NewWatcher("photo", rules, notifier)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- w.Run(ctx, NewClient(server.URI), 10*time.Millisecond)
+	}()
+
+	deadline := time.After(5 * time.Second)
+	var alerts []Alert
+	for len(alerts) == 0 {
+		select {
+		case <-deadline:
+			t.Fatal("no alert within 5s")
+		case <-time.After(10 * time.Millisecond):
+		}
+		notifier.mu.Lock()
+		alerts = slices.Clone(notifier.alerts)
+		notifier.mu.Unlock()
+	}
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("Run() error = %v, want context.Canceled", err)
+	}
+
+	want := Alert{Time: alerts[0].Time, Printer: "photo", Rule: "ink<15", Message: "Black ink low: 8% (below 15%)", Info: alerts[0].Info}
+	if !reflect.DeepEqual(alerts[0], want) || alerts[0].Info == nil {
+		t.Errorf("alert = %+v, want %+v", alerts[0], want)
+	}
+}